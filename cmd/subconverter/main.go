@@ -27,6 +27,7 @@ func main() {
 	// Create service
 	service := converter.NewService(cfg, log)
 	service.RegisterGenerators()
+	service.StartRuleProviderRefresh(context.Background(), time.Duration(cfg.Cache.TTL)*time.Second)
 
 	// Create router
 	router := server.NewRouter(service, cfg)
@@ -63,5 +64,9 @@ func main() {
 		log.WithError(err).Error("Server forced to shutdown")
 	}
 
+	if err := service.Close(); err != nil {
+		log.WithError(err).Error("Failed to release service resources")
+	}
+
 	log.Info("Server exited")
 }
\ No newline at end of file
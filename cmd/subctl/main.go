@@ -3,10 +3,13 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/cobra"
 	"github.com/subconverter/subconverter-go/internal/app/converter"
 	"github.com/subconverter/subconverter-go/internal/infra/config"
+	"github.com/subconverter/subconverter-go/internal/infra/queue"
 	"github.com/subconverter/subconverter-go/internal/pkg/logger"
 )
 
@@ -46,6 +49,54 @@ var infoCmd = &cobra.Command{
 	Run:   runInfo,
 }
 
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage recurring conversion schedules",
+	Long: `Register, list, and remove cron-scheduled recurring subscription
+conversions, processed by a running subworker's Scheduler.`,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add [urls...]",
+	Short: "Register a recurring conversion",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runScheduleAdd,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered recurring conversions",
+	Run:   runScheduleList,
+}
+
+var scheduleRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Remove a recurring conversion",
+	Args:  cobra.ExactArgs(1),
+	Run:   runScheduleRm,
+}
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Inspect and manage jobs that exhausted their retries",
+	Long: `List jobs a running subworker's Worker moved to the dead-letter
+list after MaxAttempts failed Nack calls, and requeue them by hand once
+the underlying issue is fixed.`,
+}
+
+var queueListDeadCmd = &cobra.Command{
+	Use:   "list-dead",
+	Short: "List dead-lettered jobs",
+	Run:   runQueueListDead,
+}
+
+var queueRequeueCmd = &cobra.Command{
+	Use:   "requeue <job-id>",
+	Short: "Requeue a dead-lettered job for another attempt",
+	Args:  cobra.ExactArgs(1),
+	Run:   runQueueRequeue,
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -61,10 +112,180 @@ func init() {
 	convertCmd.Flags().Bool("udp", true, "enable UDP support")
 	convertCmd.Flags().Bool("sort", true, "sort proxies by name")
 	convertCmd.Flags().Bool("insecure", false, "skip TLS verification")
+	convertCmd.Flags().String("proxy", "", "upstream proxy for subscription fetches (http://, https://, or socks5://)")
+	convertCmd.Flags().String("proxy-auth-file", "", "bcrypt user file --proxy's credentials must match")
+
+	scheduleCmd.PersistentFlags().String("queue", "memory", "queue backend the schedule is stored in (memory, redis); memory only makes sense when talking to an in-process subworker")
+	scheduleAddCmd.Flags().String("cron", "", "cron expression (robfig/cron v3 standard 5-field syntax), required")
+	scheduleAddCmd.Flags().StringP("target", "t", "clash", "target format (clash, surge, quantumult, loon, v2ray, surfboard)")
+	scheduleAddCmd.Flags().String("webhook", "", "URL to POST the conversion result or error to after each run")
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRmCmd)
+
+	queueCmd.PersistentFlags().String("queue", "memory", "queue backend (memory, redis, rabbitmq)")
+	queueCmd.AddCommand(queueListDeadCmd)
+	queueCmd.AddCommand(queueRequeueCmd)
 
 	rootCmd.AddCommand(convertCmd)
 	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(queueCmd)
+}
+
+// buildQueue opens the queue backend named by queueType. Unlike
+// buildScheduleQueue, AMQPQueue is included here: it implements
+// ListDead/Requeue even though it can't support Schedule.
+func buildQueue(queueType string, cfg *config.Config, log *logger.Logger) (queue.Queue, error) {
+	switch queueType {
+	case "redis":
+		return queue.NewRedisQueue(cfg.Redis)
+	case "rabbitmq":
+		return queue.NewAMQPQueue(cfg.AMQP, log)
+	case "memory":
+		return queue.NewMemoryQueue(), nil
+	default:
+		return nil, fmt.Errorf("unknown queue backend %q (use memory, redis, or rabbitmq)", queueType)
+	}
+}
+
+// buildScheduleQueue opens the queue backend named by queueType, which
+// must support Queue's scheduling methods (MemoryQueue and RedisQueue;
+// see queue.AMQPQueue's errScheduleUnsupported).
+func buildScheduleQueue(queueType string, cfg *config.Config) (queue.Queue, error) {
+	switch queueType {
+	case "redis":
+		return queue.NewRedisQueue(cfg.Redis)
+	case "memory":
+		return queue.NewMemoryQueue(), nil
+	default:
+		return nil, fmt.Errorf("scheduling is not supported for queue backend %q (use memory or redis)", queueType)
+	}
+}
+
+func runScheduleAdd(cmd *cobra.Command, args []string) {
+	cfg := config.Load()
+	log := logger.New(logger.Config{Level: logLevel, Format: logFormat, Output: "stdout"})
+
+	cronExpr, _ := cmd.Flags().GetString("cron")
+	target, _ := cmd.Flags().GetString("target")
+	webhook, _ := cmd.Flags().GetString("webhook")
+	queueType, _ := cmd.Flags().GetString("queue")
+
+	if cronExpr == "" {
+		log.Fatal("--cron is required")
+	}
+
+	schedule, err := cron.ParseStandard(cronExpr)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid cron expression")
+	}
+
+	q, err := buildScheduleQueue(queueType, cfg)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to open queue backend")
+	}
+
+	job := &queue.ScheduledJob{
+		Expression: cronExpr,
+		Request: converter.ConvertRequest{
+			Target: target,
+			URLs:   args,
+		},
+		WebhookURL: webhook,
+		NextRunAt:  schedule.Next(time.Now()),
+	}
+
+	if err := q.Schedule(cmd.Context(), job); err != nil {
+		log.WithError(err).Fatal("Failed to register schedule")
+	}
+
+	fmt.Printf("Scheduled %s (next run %s)\n", job.ID, job.NextRunAt.Format(time.RFC3339))
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) {
+	cfg := config.Load()
+	log := logger.New(logger.Config{Level: logLevel, Format: logFormat, Output: "stdout"})
+	queueType, _ := cmd.Flags().GetString("queue")
+
+	q, err := buildScheduleQueue(queueType, cfg)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to open queue backend")
+	}
+
+	schedules, err := q.ListSchedules(cmd.Context())
+	if err != nil {
+		log.WithError(err).Fatal("Failed to list schedules")
+	}
+
+	if len(schedules) == 0 {
+		fmt.Println("No scheduled conversions registered.")
+		return
+	}
+
+	for _, s := range schedules {
+		fmt.Printf("%s  %-20s next=%s  last_status=%s\n", s.ID, s.Expression, s.NextRunAt.Format(time.RFC3339), s.LastStatus)
+	}
+}
+
+func runScheduleRm(cmd *cobra.Command, args []string) {
+	cfg := config.Load()
+	log := logger.New(logger.Config{Level: logLevel, Format: logFormat, Output: "stdout"})
+	queueType, _ := cmd.Flags().GetString("queue")
+
+	q, err := buildScheduleQueue(queueType, cfg)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to open queue backend")
+	}
+
+	if err := q.Unschedule(cmd.Context(), args[0]); err != nil {
+		log.WithError(err).Fatal("Failed to remove schedule")
+	}
+
+	fmt.Printf("Removed schedule %s\n", args[0])
+}
+
+func runQueueListDead(cmd *cobra.Command, args []string) {
+	cfg := config.Load()
+	log := logger.New(logger.Config{Level: logLevel, Format: logFormat, Output: "stdout"})
+	queueType, _ := cmd.Flags().GetString("queue")
+
+	q, err := buildQueue(queueType, cfg, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to open queue backend")
+	}
+
+	jobs, err := q.ListDead(cmd.Context())
+	if err != nil {
+		log.WithError(err).Fatal("Failed to list dead-lettered jobs")
+	}
+
+	if len(jobs) == 0 {
+		fmt.Println("No dead-lettered jobs.")
+		return
+	}
+
+	for _, job := range jobs {
+		fmt.Printf("%s  attempts=%d/%d  error=%q\n", job.ID, job.Attempts, job.MaxAttempts, job.Error)
+	}
+}
+
+func runQueueRequeue(cmd *cobra.Command, args []string) {
+	cfg := config.Load()
+	log := logger.New(logger.Config{Level: logLevel, Format: logFormat, Output: "stdout"})
+	queueType, _ := cmd.Flags().GetString("queue")
+
+	q, err := buildQueue(queueType, cfg, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to open queue backend")
+	}
+
+	if err := q.Requeue(cmd.Context(), args[0]); err != nil {
+		log.WithError(err).Fatal("Failed to requeue job")
+	}
+
+	fmt.Printf("Requeued %s\n", args[0])
 }
 
 func initConfig() {
@@ -87,6 +308,15 @@ func runConvert(cmd *cobra.Command, args []string) {
 	exclude, _ := cmd.Flags().GetStringSlice("exclude")
 	udp, _ := cmd.Flags().GetBool("udp")
 	sort, _ := cmd.Flags().GetBool("sort")
+	proxy, _ := cmd.Flags().GetString("proxy")
+	proxyAuthFile, _ := cmd.Flags().GetString("proxy-auth-file")
+
+	if proxy != "" {
+		cfg.Fetcher.ProxyURL = proxy
+	}
+	if proxyAuthFile != "" {
+		cfg.Fetcher.ProxyAuthFile = proxyAuthFile
+	}
 
 	service := converter.NewService(cfg, log)
 	service.RegisterGenerators()
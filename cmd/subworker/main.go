@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/subconverter/subconverter-go/internal/app/converter"
 	"github.com/subconverter/subconverter-go/internal/infra/config"
@@ -15,9 +18,10 @@ import (
 )
 
 var (
-	cfgFile string
-	workers int
-	queueType string
+	cfgFile     string
+	workers     int
+	queueType   string
+	metricsAddr string
 )
 
 var rootCmd = &cobra.Command{
@@ -47,7 +51,8 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./configs/config.yaml)")
 	rootCmd.PersistentFlags().IntVarP(&workers, "workers", "w", 1, "number of worker goroutines")
-	rootCmd.PersistentFlags().StringVarP(&queueType, "queue", "q", "memory", "queue backend (memory, redis)")
+	rootCmd.PersistentFlags().StringVarP(&queueType, "queue", "q", "memory", "queue backend (memory, redis, rabbitmq)")
+	rootCmd.PersistentFlags().StringVar(&metricsAddr, "metrics-addr", ":9101", "address the worker's Prometheus /metrics endpoint listens on")
 
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(processCmd)
@@ -71,6 +76,8 @@ func runStart(cmd *cobra.Command, args []string) {
 	switch queueType {
 	case "redis":
 		q, err = queue.NewRedisQueue(cfg.Redis)
+	case "rabbitmq":
+		q, err = queue.NewAMQPQueue(cfg.AMQP, log)
 	default:
 		q = queue.NewMemoryQueue()
 	}
@@ -81,6 +88,7 @@ func runStart(cmd *cobra.Command, args []string) {
 
 	service := converter.NewService(cfg, log)
 	worker := queue.NewWorker(q, service, *log)
+	scheduler := queue.NewScheduler(q, service, log)
 
 	log.WithFields(map[string]interface{}{
 		"workers":    workers,
@@ -100,6 +108,19 @@ func runStart(cmd *cobra.Command, args []string) {
 		cancel()
 	}()
 
+	queue.StartDepthMetrics(ctx, q, queueType, 15*time.Second)
+
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		log.WithField("addr", metricsAddr).Info("Starting worker metrics server...")
+		if err := http.ListenAndServe(metricsAddr, mux); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("Metrics server failed")
+		}
+	}()
+
+	go scheduler.Start(ctx)
+
 	if err := worker.Start(ctx, workers); err != nil {
 		log.WithError(err).Fatal("Worker failed")
 	}
@@ -0,0 +1,136 @@
+package clash
+
+import (
+	stderrors "errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+)
+
+// defaultDelayTimeout bounds GET /proxies/:name/delay when the caller's
+// ?timeout= query param is missing or invalid.
+const defaultDelayTimeout = 5 * time.Second
+
+// Controller serves the Clash-compatible external controller API over a
+// Registry, mounted by Router.SetupRoutes when config.Clash.Enabled.
+type Controller struct {
+	registry *Registry
+	logHook  *LogHook
+	secret   string
+}
+
+// NewController builds a Controller. secret, when non-empty, is
+// required as a Bearer token on every route (see Controller.auth),
+// matching Clash's own external-controller authentication.
+func NewController(registry *Registry, logHook *LogHook, secret string) *Controller {
+	return &Controller{registry: registry, logHook: logHook, secret: secret}
+}
+
+// Register mounts every Clash endpoint onto app, rooted at "/" to match
+// the paths real Clash clients and dashboards expect - "/proxies", not
+// "/api/v1/proxies".
+func (ctrl *Controller) Register(app fiber.Router) {
+	app.Use(ctrl.auth)
+
+	app.Get("/proxies", ctrl.listProxies)
+	app.Get("/proxies/:name", ctrl.getProxy)
+	app.Put("/proxies/:name", ctrl.selectProxy)
+	app.Get("/proxies/:name/delay", ctrl.delayProxy)
+	app.Get("/rules", ctrl.listRules)
+	app.Get("/connections", ctrl.listConnections)
+	app.Get("/traffic", websocket.New(ctrl.handleTraffic))
+	app.Get("/logs", websocket.New(ctrl.handleLogs))
+}
+
+// auth rejects requests missing "Authorization: Bearer <secret>" when a
+// secret is configured, same as Clash's external-controller "secret".
+func (ctrl *Controller) auth(c *fiber.Ctx) error {
+	if ctrl.secret == "" {
+		return c.Next()
+	}
+	if c.Get("Authorization") != "Bearer "+ctrl.secret {
+		return ctrl.errorResponse(c, errors.Unauthorized("UNAUTHORIZED", "invalid or missing secret"))
+	}
+	return c.Next()
+}
+
+func (ctrl *Controller) listProxies(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"proxies": ctrl.registry.Proxies()})
+}
+
+func (ctrl *Controller) getProxy(c *fiber.Ctx) error {
+	info, ok := ctrl.registry.Proxy(c.Params("name"))
+	if !ok {
+		return ctrl.errorResponse(c, errors.NotFound("PROXY_NOT_FOUND", "proxy not found"))
+	}
+	return c.JSON(info)
+}
+
+// selectRequest is the PUT /proxies/:name body, matching Clash's own
+// {"name": "..."} shape.
+type selectRequest struct {
+	Name string `json:"name"`
+}
+
+func (ctrl *Controller) selectProxy(c *fiber.Ctx) error {
+	var req selectRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.errorResponse(c, errors.BadRequest("INVALID_REQUEST", err.Error()))
+	}
+
+	if err := ctrl.registry.Select(c.Params("name"), req.Name); err != nil {
+		return ctrl.errorResponse(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// delayProxy re-probes a single proxy's latency. Clash clients also send
+// a ?url= to round-trip an HTTP request through the proxy, but this
+// codebase has no per-protocol dial transport to honor that with (see
+// the healthcheck package's doc comment) - every probe is the same
+// plain TCP connect Options.Measure uses, so ?url= is accepted for
+// client compatibility and otherwise ignored.
+func (ctrl *Controller) delayProxy(c *fiber.Ctx) error {
+	timeout := defaultDelayTimeout
+	if raw := c.Query("timeout"); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+			timeout = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	delay, err := ctrl.registry.Delay(c.Context(), c.Params("name"), timeout)
+	if err != nil {
+		return ctrl.errorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"delay": delay})
+}
+
+func (ctrl *Controller) listRules(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"rules": ctrl.registry.Rules()})
+}
+
+// listConnections reports the per-proxy traffic totals the traffic
+// controller has recorded, in Clash's {"connections":[...]} shape. Real
+// Clash also reports a "downloadTotal"/"uploadTotal" pair and one row
+// per live socket with source/destination/rule metadata; this codebase
+// only has the aggregate per proxy (see Registry.Connections).
+func (ctrl *Controller) listConnections(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"connections": ctrl.registry.Connections()})
+}
+
+// errorResponse serializes err the way Clash clients expect it: a bare
+// {"message": ...}, unlike the rest of the API's {"error","code",
+// "details"} shape (see Router.errorResponse). The message is translated
+// per the request's Accept-Language header when err's code is registered
+// with one (see errors.Register).
+func (ctrl *Controller) errorResponse(c *fiber.Ctx, err error) error {
+	var appErr *errors.Error
+	if stderrors.As(err, &appErr) {
+		return c.Status(appErr.Status).JSON(fiber.Map{"message": appErr.Localize(c.Get("Accept-Language"))})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+}
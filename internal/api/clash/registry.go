@@ -0,0 +1,306 @@
+// Package clash exposes a Clash-compatible external controller surface
+// over the converter service's most recent conversion: GET /proxies and
+// GET /proxies/:name report the Clash proxy/group JSON shape, PUT
+// /proxies/:name switches a selector's current member, GET
+// /proxies/:name/delay re-probes a single proxy's latency, GET /rules
+// mirrors the custom rule list the last conversion used, GET
+// /connections reports the traffic controller's per-proxy byte counts
+// (see the adapter package), and GET /traffic and GET /logs stream over
+// a websocket.
+//
+// There is no long-running proxy core in this codebase - subconverter
+// only ever renders a config for a client to load elsewhere, it never
+// dials through one - so this package has no "currently running"
+// session of its own to report on. Instead Registry snapshots whichever
+// request last completed Service.Convert, which is good enough for a
+// dashboard that just asked this instance to convert and wants to poke
+// at the result.
+package clash
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/app/adapter"
+	"github.com/subconverter/subconverter-go/internal/app/fetcher"
+	"github.com/subconverter/subconverter-go/internal/app/generator"
+	"github.com/subconverter/subconverter-go/internal/app/healthcheck"
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+)
+
+// maxDelayHistory bounds how many delay-test results Registry keeps per
+// proxy, matching the length of Clash's own dashboard latency sparkline.
+const maxDelayHistory = 5
+
+// DelayRecord is one latency sample taken for a proxy, oldest first.
+type DelayRecord struct {
+	Time  time.Time `json:"time"`
+	Delay int64     `json:"delay"`
+}
+
+// group is a proxy group from the last conversion's ProxyGroups:
+// members lists its candidates in order, and selected is whichever one
+// is currently active - the group's first member until PUT
+// /proxies/:name overrides it for a "select" group.
+type group struct {
+	groupType string
+	members   []string
+	selected  string
+}
+
+// Registry holds the proxies, groups and rules from the most recently
+// completed Convert call, plus any selector switches and delay-test
+// history recorded since. It is safe for concurrent use.
+type Registry struct {
+	prober            *healthcheck.Prober
+	trafficController adapter.TrafficController
+
+	mu      sync.RWMutex
+	proxies map[string]*proxy.Proxy
+	groups  map[string]*group
+	rules   []string
+	traffic *fetcher.UserInfo
+	history map[string][]DelayRecord
+}
+
+// NewRegistry builds an empty Registry. prober is reused for delay
+// tests, so GET /proxies/:name/delay probes and caches results the same
+// way a Measure=true Convert call does. tc backs GET /connections with
+// the per-proxy byte counts recorded by the traffic controller; it may
+// be nil, in which case GET /connections reports no connections.
+func NewRegistry(prober *healthcheck.Prober, tc adapter.TrafficController) *Registry {
+	return &Registry{
+		prober:            prober,
+		trafficController: tc,
+		proxies:           make(map[string]*proxy.Proxy),
+		groups:            make(map[string]*group),
+		history:           make(map[string][]DelayRecord),
+	}
+}
+
+// Update replaces the snapshot with the result of a conversion. An
+// existing selector choice is preserved for any group whose members are
+// unchanged, so re-converting the same subscription doesn't reset a
+// user's selection.
+func (r *Registry) Update(proxies []*proxy.Proxy, groups []generator.ProxyGroup, rules []string, traffic *fetcher.UserInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	proxyByName := make(map[string]*proxy.Proxy, len(proxies))
+	for _, p := range proxies {
+		proxyByName[p.Name] = p
+	}
+	r.proxies = proxyByName
+
+	newGroups := make(map[string]*group, len(groups))
+	for _, g := range groups {
+		selected := ""
+		if len(g.Proxies) > 0 {
+			selected = g.Proxies[0]
+		}
+		if old, ok := r.groups[g.Name]; ok && containsString(g.Proxies, old.selected) {
+			selected = old.selected
+		}
+		newGroups[g.Name] = &group{groupType: g.Type, members: g.Proxies, selected: selected}
+	}
+	r.groups = newGroups
+	r.rules = rules
+	r.traffic = traffic
+}
+
+// Proxies returns every proxy and group from the last conversion in the
+// Clash "/proxies" shape, keyed by name.
+func (r *Registry) Proxies() map[string]ProxyInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]ProxyInfo, len(r.proxies)+len(r.groups))
+	for name, p := range r.proxies {
+		out[name] = ProxyInfo{
+			Name:    name,
+			Type:    clashType(p.Type),
+			UDP:     p.UDP,
+			History: r.historyLocked(name),
+		}
+	}
+	for name, g := range r.groups {
+		out[name] = ProxyInfo{
+			Name:    name,
+			Type:    clashGroupType(g.groupType),
+			Now:     g.selected,
+			All:     g.members,
+			History: r.historyLocked(name),
+		}
+	}
+	return out
+}
+
+// Proxy returns a single proxy or group by name.
+func (r *Registry) Proxy(name string) (ProxyInfo, bool) {
+	info, ok := r.Proxies()[name]
+	return info, ok
+}
+
+// historyLocked returns a copy of name's delay history; callers must
+// already hold r.mu for reading.
+func (r *Registry) historyLocked(name string) []DelayRecord {
+	records := r.history[name]
+	out := make([]DelayRecord, len(records))
+	copy(out, records)
+	return out
+}
+
+// ErrNotSelector is returned by Select when name isn't a "select" group
+// - a url-test/fallback/load-balance/relay group picks its own member,
+// so it can't be switched from the outside.
+var ErrNotSelector = errors.BadRequest("NOT_SELECTOR", "proxy is not a selector group")
+
+// Select switches groupName's current member to member, matching
+// Clash's PUT /proxies/:name.
+func (r *Registry) Select(groupName, member string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, ok := r.groups[groupName]
+	if !ok {
+		return errors.NotFound("PROXY_NOT_FOUND", fmt.Sprintf("proxy group %q not found", groupName))
+	}
+	if g.groupType != "select" {
+		return ErrNotSelector
+	}
+	if !containsString(g.members, member) {
+		return errors.BadRequest("INVALID_SELECTION", fmt.Sprintf("%q is not a member of %q", member, groupName))
+	}
+
+	g.selected = member
+	return nil
+}
+
+// Delay probes name (a plain proxy, not a group) through the prober,
+// bounded by timeout, records the result in that proxy's history, and
+// returns the round-trip time in milliseconds. The prober returns its
+// cached result for the same server instead of probing again while
+// that result is within its TTL.
+func (r *Registry) Delay(ctx context.Context, name string, timeout time.Duration) (int64, error) {
+	r.mu.RLock()
+	p, ok := r.proxies[name]
+	r.mu.RUnlock()
+	if !ok {
+		return 0, errors.NotFound("PROXY_NOT_FOUND", fmt.Sprintf("proxy %q not found", name))
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	probe := *p
+	r.prober.Measure(probeCtx, []*proxy.Proxy{&probe})
+	if !probe.Alive {
+		return 0, errors.NewWithStatus("DELAY_TIMEOUT", "proxy did not respond within the given timeout", 504)
+	}
+
+	r.mu.Lock()
+	history := append(r.history[name], DelayRecord{Time: time.Now(), Delay: probe.Latency})
+	if len(history) > maxDelayHistory {
+		history = history[len(history)-maxDelayHistory:]
+	}
+	r.history[name] = history
+	r.mu.Unlock()
+
+	return probe.Latency, nil
+}
+
+// RuleInfo is one entry in Clash's GET /rules response.
+type RuleInfo struct {
+	Type    string `json:"type"`
+	Payload string `json:"payload"`
+	Proxy   string `json:"proxy"`
+}
+
+// Rules returns the custom rule list the last conversion used, each
+// parsed from its "TYPE,VALUE[,POLICY]" wire form back into Clash's
+// {type,payload,proxy} shape.
+func (r *Registry) Rules() []RuleInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make([]RuleInfo, 0, len(r.rules))
+	for _, raw := range r.rules {
+		parts := splitRule(raw)
+		info := RuleInfo{Type: parts[0]}
+		if len(parts) > 1 {
+			info.Payload = parts[1]
+		}
+		if len(parts) > 2 {
+			info.Proxy = parts[2]
+		}
+		rules = append(rules, info)
+	}
+	return rules
+}
+
+// Traffic returns the upload/download byte counters the last
+// conversion's subscription providers reported via
+// "subscription-userinfo". It is not a live per-second rate - this
+// codebase never dials through a proxy, so there is no ongoing traffic
+// to sample - it is whatever the upstream subscription last reported.
+func (r *Registry) Traffic() (up, down int64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.traffic == nil {
+		return 0, 0
+	}
+	return r.traffic.Upload, r.traffic.Download
+}
+
+// ConnectionInfo is one entry in Clash's GET /connections response,
+// reporting the running totals a proxy's ConnectionTracker has
+// accumulated rather than one row per live socket - this codebase has
+// no per-connection session to report on (see the package doc comment),
+// only the aggregate the traffic controller has seen for that proxy so
+// far.
+type ConnectionInfo struct {
+	Proxy    string `json:"proxy"`
+	Upload   int64  `json:"upload"`
+	Download int64  `json:"download"`
+}
+
+// Connections returns one ConnectionInfo per proxy the traffic
+// controller has routed a connection through, named using the last
+// conversion's proxy names where the proxy ID is still known. It
+// returns an empty slice if no controller was configured.
+func (r *Registry) Connections() []ConnectionInfo {
+	if r.trafficController == nil {
+		return []ConnectionInfo{}
+	}
+
+	r.mu.RLock()
+	nameByID := make(map[string]string, len(r.proxies))
+	for name, p := range r.proxies {
+		nameByID[p.ID] = name
+	}
+	r.mu.RUnlock()
+
+	trackers := r.trafficController.Trackers()
+	out := make([]ConnectionInfo, 0, len(trackers))
+	for id, tracker := range trackers {
+		stats := tracker.Stats()
+		name := nameByID[id]
+		if name == "" {
+			name = id
+		}
+		out = append(out, ConnectionInfo{Proxy: name, Upload: stats.Upload, Download: stats.Download})
+	}
+	return out
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package clash
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// trafficInterval is how often GET /traffic pushes a frame, matching
+// Clash's own one-second cadence.
+const trafficInterval = time.Second
+
+// handleTraffic streams Registry.Traffic() once a second until the
+// client disconnects, in Clash's {"up":...,"down":...} shape.
+func (ctrl *Controller) handleTraffic(conn *websocket.Conn) {
+	defer conn.Close()
+
+	ticker := time.NewTicker(trafficInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		up, down := ctrl.registry.Traffic()
+		if err := conn.WriteJSON(struct {
+			Up   int64 `json:"up"`
+			Down int64 `json:"down"`
+		}{Up: up, Down: down}); err != nil {
+			return
+		}
+	}
+}
+
+// handleLogs streams every entry logged through ctrl.logHook's attached
+// *logger.Logger until the client disconnects, in Clash's
+// {"type":...,"payload":...} shape.
+func (ctrl *Controller) handleLogs(conn *websocket.Conn) {
+	if ctrl.logHook == nil {
+		conn.Close()
+		return
+	}
+
+	ch := ctrl.logHook.subscribe()
+	defer ctrl.logHook.unsubscribe(ch)
+	defer conn.Close()
+
+	for entry := range ch {
+		if err := conn.WriteJSON(entry); err != nil {
+			return
+		}
+	}
+}
+
+// logEntry is one line streamed over GET /logs.
+type logEntry struct {
+	Type    string `json:"type"`
+	Payload string `json:"payload"`
+}
+
+// logSubscriberBuffer bounds how many pending entries a slow GET /logs
+// client can fall behind by before LogHook starts dropping lines for it
+// rather than blocking the logger that feeds every subscriber.
+const logSubscriberBuffer = 64
+
+// LogHook is a logrus.Hook that fans out every entry logged through the
+// *logger.Logger it is attached to (via AddHook) to whichever GET /logs
+// websocket clients are currently connected, mirroring Clash's own
+// log-streaming endpoint.
+type LogHook struct {
+	mu   sync.Mutex
+	subs map[chan logEntry]struct{}
+}
+
+// NewLogHook builds an empty LogHook. Attach it with
+// (*logger.Logger).AddHook before any log lines that should reach GET
+// /logs are written.
+func NewLogHook() *LogHook {
+	return &LogHook{subs: make(map[chan logEntry]struct{})}
+}
+
+// Levels reports that LogHook fires for every level, so GET /logs sees
+// the same lines the configured log level lets through everywhere else.
+func (h *LogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook, broadcasting entry to every subscriber.
+// A subscriber whose buffer is full has the entry dropped rather than
+// blocking the caller that's logging.
+func (h *LogHook) Fire(entry *logrus.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return err
+	}
+	e := logEntry{Type: entry.Level.String(), Payload: strings.TrimRight(line, "\n")}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	return nil
+}
+
+func (h *LogHook) subscribe() chan logEntry {
+	ch := make(chan logEntry, logSubscriberBuffer)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *LogHook) unsubscribe(ch chan logEntry) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+	close(ch)
+}
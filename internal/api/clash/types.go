@@ -0,0 +1,75 @@
+package clash
+
+import (
+	"strings"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+)
+
+// ProxyInfo is the Clash proxy/group JSON shape returned by GET
+// /proxies and GET /proxies/:name.
+type ProxyInfo struct {
+	Name    string        `json:"name"`
+	Type    string        `json:"type"`
+	UDP     bool          `json:"udp"`
+	Now     string        `json:"now,omitempty"`
+	All     []string      `json:"all,omitempty"`
+	History []DelayRecord `json:"history"`
+}
+
+// clashType maps a proxy.Type to the capitalized string Clash's own
+// external controller reports for it (e.g. "Shadowsocks", "Vmess").
+// Types Clash has no equivalent protocol for (plain http/https/socks5/ssh)
+// are passed through unchanged so a client at least sees something
+// meaningful instead of an empty string.
+func clashType(t proxy.Type) string {
+	switch t {
+	case proxy.TypeShadowsocks:
+		return "Shadowsocks"
+	case proxy.TypeShadowsocksR:
+		return "ShadowsocksR"
+	case proxy.TypeVMess:
+		return "Vmess"
+	case proxy.TypeVLESS:
+		return "Vless"
+	case proxy.TypeTrojan:
+		return "Trojan"
+	case proxy.TypeHysteria:
+		return "Hysteria"
+	case proxy.TypeHysteria2:
+		return "Hysteria2"
+	case proxy.TypeSnell:
+		return "Snell"
+	default:
+		return string(t)
+	}
+}
+
+// clashGroupType maps a generator.ProxyGroup's Type (e.g. "select",
+// "url-test") to the capitalized name Clash reports for a group.
+func clashGroupType(t string) string {
+	switch t {
+	case "select":
+		return "Selector"
+	case "url-test":
+		return "URLTest"
+	case "fallback":
+		return "Fallback"
+	case "load-balance":
+		return "LoadBalance"
+	case "relay":
+		return "Relay"
+	default:
+		return "Selector"
+	}
+}
+
+// splitRule splits one "TYPE,VALUE[,POLICY]" rule string into its
+// trimmed parts, same wire form generator.ClashGenerator emits.
+func splitRule(raw string) []string {
+	parts := strings.SplitN(raw, ",", 3)
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
@@ -0,0 +1,103 @@
+package adapter
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// countingConn wraps a net.Conn, feeding every Read/Write byte count
+// into tracker and, once on the first Close, publishing a Record to
+// records.
+type countingConn struct {
+	net.Conn
+
+	tracker *ConnectionTracker
+	ruleKey string
+	source  string
+	dest    string
+	started time.Time
+	records chan<- Record
+
+	closeOnce sync.Once
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.tracker.addDownload(int64(n))
+	}
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.tracker.addUpload(int64(n))
+	}
+	return n, err
+}
+
+func (c *countingConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(func() { publish(c.records, c.tracker, c.ruleKey, c.source, c.dest, c.started) })
+	return err
+}
+
+// countingPacketConn is countingConn for a net.PacketConn: ReadFrom/
+// WriteTo carry the payload instead of Read/Write, and the peer address
+// is only known per-datagram, so Record.Destination is left blank.
+type countingPacketConn struct {
+	net.PacketConn
+
+	tracker *ConnectionTracker
+	ruleKey string
+	source  string
+	started time.Time
+	records chan<- Record
+
+	closeOnce sync.Once
+}
+
+func (c *countingPacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, addr, err := c.PacketConn.ReadFrom(b)
+	if n > 0 {
+		c.tracker.addDownload(int64(n))
+	}
+	return n, addr, err
+}
+
+func (c *countingPacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	n, err := c.PacketConn.WriteTo(b, addr)
+	if n > 0 {
+		c.tracker.addUpload(int64(n))
+	}
+	return n, err
+}
+
+func (c *countingPacketConn) Close() error {
+	err := c.PacketConn.Close()
+	c.closeOnce.Do(func() { publish(c.records, c.tracker, c.ruleKey, c.source, "", c.started) })
+	return err
+}
+
+// publish writes tracker's current totals to records as a Record,
+// dropping it instead of blocking if the channel is full.
+func publish(records chan<- Record, tracker *ConnectionTracker, ruleKey, source, dest string, started time.Time) {
+	stats := tracker.Stats()
+	record := Record{
+		ProxyID:     tracker.ProxyID,
+		RuleKey:     ruleKey,
+		Source:      source,
+		Destination: dest,
+		Upload:      stats.Upload,
+		Download:    stats.Download,
+		Started:     started,
+		Elapsed:     time.Since(started),
+	}
+
+	select {
+	case records <- record:
+	default:
+	}
+}
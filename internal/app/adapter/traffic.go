@@ -0,0 +1,237 @@
+// Package adapter wraps outbound connections with byte counters so a
+// proxy's in-flight traffic can be attributed back to it - and, where
+// known, to the rule that routed it - modeled on sing-box's
+// TrafficController/RoutedConnection design.
+//
+// subconverter has no long-running proxy core of its own (see the
+// clash package's doc comment): it renders a config for a client to
+// load elsewhere, it never dials an outbound connection through a
+// parsed *proxy.Proxy to carry a client's traffic. The only dialer that
+// exists today is healthcheck.Prober's plain TCP connect, wired through
+// SetTrafficController, so RoutedConnection only ever sees that probe
+// connection in practice. It is built to the shape a future
+// per-protocol dialer (ss/vmess/trojan/hysteria2) needs, so wiring one
+// in later is a one-line change at the dial site rather than a
+// redesign.
+package adapter
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+)
+
+// maxSamples bounds how many throughput samples a ConnectionTracker
+// keeps as a short sparkline history, like the clash package's
+// maxDelayHistory (which keeps 5 delay records per proxy).
+const maxSamples = 30
+
+// recordBuffer bounds how many finished-connection records Controller
+// queues before Records() is drained; a slow consumer drops new records
+// rather than blocking the connection that just closed.
+const recordBuffer = 64
+
+// Sample is one point-in-time cumulative throughput reading.
+type Sample struct {
+	Time     time.Time `json:"time"`
+	Upload   int64     `json:"upload"`
+	Download int64     `json:"download"`
+}
+
+// Record is the summary written to Controller.Records() when a routed
+// connection closes.
+type Record struct {
+	ProxyID     string        `json:"proxy_id"`
+	RuleKey     string        `json:"rule_key,omitempty"`
+	Source      string        `json:"source,omitempty"`
+	Destination string        `json:"destination,omitempty"`
+	Upload      int64         `json:"upload"`
+	Download    int64         `json:"download"`
+	Started     time.Time     `json:"started"`
+	Elapsed     time.Duration `json:"elapsed"`
+}
+
+// ConnectionTracker accumulates byte counts for every connection routed
+// through a single proxy, keyed by proxy.Proxy.ID.
+type ConnectionTracker struct {
+	ProxyID string
+
+	mu       sync.Mutex
+	upload   int64
+	download int64
+	samples  []Sample
+}
+
+func newConnectionTracker(proxyID string) *ConnectionTracker {
+	return &ConnectionTracker{ProxyID: proxyID}
+}
+
+// Stats returns the running totals as a proxy.Statistics. Latency is
+// always zero here - that field is owned by healthcheck.StatsStore -
+// callers that want both merge the two by proxy ID.
+func (t *ConnectionTracker) Stats() proxy.Statistics {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return proxy.Statistics{Upload: t.upload, Download: t.download}
+}
+
+// Samples returns a copy of the recorded throughput history, oldest
+// first.
+func (t *ConnectionTracker) Samples() []Sample {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Sample, len(t.samples))
+	copy(out, t.samples)
+	return out
+}
+
+func (t *ConnectionTracker) addUpload(n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.upload += n
+	t.appendSampleLocked()
+}
+
+func (t *ConnectionTracker) addDownload(n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.download += n
+	t.appendSampleLocked()
+}
+
+// appendSampleLocked requires t.mu to already be held.
+func (t *ConnectionTracker) appendSampleLocked() {
+	t.samples = append(t.samples, Sample{Time: time.Now(), Upload: t.upload, Download: t.download})
+	if len(t.samples) > maxSamples {
+		t.samples = t.samples[len(t.samples)-maxSamples:]
+	}
+}
+
+// TrafficController wraps outbound connections dialed for a proxy so
+// their byte counts can be attributed back to it (and, when the caller
+// knows which rule matched, to that rule too).
+type TrafficController interface {
+	// RoutedConnection wraps conn, counting every byte read/written into
+	// prx's ConnectionTracker until conn is closed.
+	RoutedConnection(ctx context.Context, conn net.Conn, prx *proxy.Proxy, rule *ruleset.Rule) net.Conn
+	// RoutedPacketConnection is RoutedConnection for a net.PacketConn.
+	RoutedPacketConnection(ctx context.Context, conn net.PacketConn, prx *proxy.Proxy, rule *ruleset.Rule) net.PacketConn
+	// Tracker returns the ConnectionTracker accumulating bytes for
+	// proxyID, if any connection has been routed through it yet.
+	Tracker(proxyID string) (*ConnectionTracker, bool)
+	// Trackers returns every ConnectionTracker created so far, keyed by
+	// proxy ID, for callers that need to enumerate them (e.g. GET
+	// /connections).
+	Trackers() map[string]*ConnectionTracker
+	// Records streams a summary of every routed connection as it closes.
+	// The Clash API does not read it: GET /traffic streams
+	// Registry.Traffic and GET /connections reads Trackers.
+	Records() <-chan Record
+}
+
+// Controller is the default TrafficController.
+type Controller struct {
+	mu       sync.RWMutex
+	trackers map[string]*ConnectionTracker
+	records  chan Record
+}
+
+// NewController builds an empty Controller.
+func NewController() *Controller {
+	return &Controller{
+		trackers: make(map[string]*ConnectionTracker),
+		records:  make(chan Record, recordBuffer),
+	}
+}
+
+func (c *Controller) trackerFor(proxyID string) *ConnectionTracker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.trackers[proxyID]
+	if !ok {
+		t = newConnectionTracker(proxyID)
+		c.trackers[proxyID] = t
+	}
+	return t
+}
+
+// Tracker implements TrafficController.
+func (c *Controller) Tracker(proxyID string) (*ConnectionTracker, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	t, ok := c.trackers[proxyID]
+	return t, ok
+}
+
+// Trackers implements TrafficController.
+func (c *Controller) Trackers() map[string]*ConnectionTracker {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]*ConnectionTracker, len(c.trackers))
+	for id, t := range c.trackers {
+		out[id] = t
+	}
+	return out
+}
+
+// Records implements TrafficController.
+func (c *Controller) Records() <-chan Record {
+	return c.records
+}
+
+// RoutedConnection implements TrafficController. prx == nil means the
+// caller has no proxy to attribute traffic to, so conn is returned
+// unwrapped.
+func (c *Controller) RoutedConnection(ctx context.Context, conn net.Conn, prx *proxy.Proxy, rule *ruleset.Rule) net.Conn {
+	if prx == nil {
+		return conn
+	}
+
+	wrapped := &countingConn{
+		Conn:    conn,
+		tracker: c.trackerFor(prx.ID),
+		ruleKey: ruleKey(rule),
+		started: time.Now(),
+		records: c.records,
+	}
+	if local := conn.LocalAddr(); local != nil {
+		wrapped.source = local.String()
+	}
+	if remote := conn.RemoteAddr(); remote != nil {
+		wrapped.dest = remote.String()
+	}
+	return wrapped
+}
+
+// RoutedPacketConnection implements TrafficController.
+func (c *Controller) RoutedPacketConnection(ctx context.Context, conn net.PacketConn, prx *proxy.Proxy, rule *ruleset.Rule) net.PacketConn {
+	if prx == nil {
+		return conn
+	}
+
+	wrapped := &countingPacketConn{
+		PacketConn: conn,
+		tracker:    c.trackerFor(prx.ID),
+		ruleKey:    ruleKey(rule),
+		started:    time.Now(),
+		records:    c.records,
+	}
+	if local := conn.LocalAddr(); local != nil {
+		wrapped.source = local.String()
+	}
+	return wrapped
+}
+
+// ruleKey identifies rule for Record.RuleKey. ruleset.Rule has no
+// stable ID of its own, so the rule's type and match value stand in for
+// one - that pair is unique within a single generated rule list.
+func ruleKey(rule *ruleset.Rule) string {
+	if rule == nil {
+		return ""
+	}
+	return string(rule.Type) + ":" + rule.Value
+}
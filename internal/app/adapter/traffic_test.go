@@ -0,0 +1,94 @@
+package adapter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+)
+
+func TestController_RoutedConnection_CountsBytesAndPublishesRecord(t *testing.T) {
+	ctrl := NewController()
+	prx := &proxy.Proxy{ID: "proxy-1"}
+	rule := &ruleset.Rule{Type: ruleset.RuleTypeDomain, Value: "example.com"}
+
+	client, server := net.Pipe()
+	defer server.Close()
+
+	routed := ctrl.RoutedConnection(context.Background(), client, prx, rule)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 5)
+		server.Read(buf)
+		server.Write([]byte("world"))
+	}()
+
+	_, err := routed.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 5)
+	n, err := routed.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(buf[:n]))
+
+	<-done
+	require.NoError(t, routed.Close())
+
+	tracker, ok := ctrl.Tracker("proxy-1")
+	require.True(t, ok)
+	stats := tracker.Stats()
+	assert.Equal(t, int64(5), stats.Upload)
+	assert.Equal(t, int64(5), stats.Download)
+
+	select {
+	case record := <-ctrl.Records():
+		assert.Equal(t, "proxy-1", record.ProxyID)
+		assert.Equal(t, "DOMAIN:example.com", record.RuleKey)
+		assert.Equal(t, int64(5), record.Upload)
+		assert.Equal(t, int64(5), record.Download)
+	case <-time.After(time.Second):
+		t.Fatal("no record published on close")
+	}
+}
+
+func TestController_RoutedConnection_NilProxyPassesThrough(t *testing.T) {
+	ctrl := NewController()
+	client, server := net.Pipe()
+	defer server.Close()
+
+	routed := ctrl.RoutedConnection(context.Background(), client, nil, nil)
+	assert.Same(t, client, routed)
+}
+
+func TestController_Tracker_UnknownProxyNotFound(t *testing.T) {
+	ctrl := NewController()
+	_, ok := ctrl.Tracker("missing")
+	assert.False(t, ok)
+}
+
+func TestController_Trackers_ListsEveryProxySeen(t *testing.T) {
+	ctrl := NewController()
+	client, server := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	ctrl.RoutedConnection(context.Background(), client, &proxy.Proxy{ID: "proxy-1"}, nil)
+
+	trackers := ctrl.Trackers()
+	require.Len(t, trackers, 1)
+	_, ok := trackers["proxy-1"]
+	assert.True(t, ok)
+}
+
+func TestRuleKey(t *testing.T) {
+	assert.Equal(t, "", ruleKey(nil))
+	assert.Equal(t, "GEOIP:CN", ruleKey(&ruleset.Rule{Type: ruleset.RuleTypeGeoIP, Value: "CN"}))
+}
@@ -0,0 +1,246 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+
+	"github.com/subconverter/subconverter-go/internal/app/fetcher"
+	"github.com/subconverter/subconverter-go/internal/app/parser"
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/infra/config"
+	apphttp "github.com/subconverter/subconverter-go/internal/infra/http"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+)
+
+// subscriptionFetcher fetches and parses subscription URLs with a bounded
+// worker pool, per-host rate limiting, and retry with exponential
+// backoff, coalescing duplicate URLs requested by concurrent Convert
+// calls via a dedicated singleflight.Group so a stampede on one provider
+// only costs a single outbound request.
+type subscriptionFetcher struct {
+	fetcher        fetcher.Fetcher
+	parserManager  *parser.Manager
+	logger         *logger.Logger
+	sem            chan struct{}
+	limiters       *hostLimiters
+	group          singleflight.Group
+	maxRetries     int
+	retryBaseDelay time.Duration
+}
+
+// newSubscriptionFetcher builds a subscriptionFetcher from cfg, defaulting
+// MaxConcurrency to runtime.GOMAXPROCS(0)*2 and RetryBaseDelayMS to 200ms
+// when unset.
+func newSubscriptionFetcher(f fetcher.Fetcher, parserManager *parser.Manager, cfg config.FetcherConfig, log *logger.Logger) *subscriptionFetcher {
+	concurrency := cfg.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0) * 2
+	}
+
+	retryBaseDelay := time.Duration(cfg.RetryBaseDelayMS) * time.Millisecond
+	if retryBaseDelay <= 0 {
+		retryBaseDelay = 200 * time.Millisecond
+	}
+
+	return &subscriptionFetcher{
+		fetcher:        f,
+		parserManager:  parserManager,
+		logger:         log,
+		sem:            make(chan struct{}, concurrency),
+		limiters:       newHostLimiters(cfg.RatePerHost, cfg.RateBurst),
+		maxRetries:     cfg.MaxRetries,
+		retryBaseDelay: retryBaseDelay,
+	}
+}
+
+// fetchOutcome is one URL's result, successful or not; metric is always
+// populated so callers can report on every URL regardless of outcome.
+type fetchOutcome struct {
+	proxies     []*proxy.Proxy
+	diagnostics []*parser.LineError
+	userInfo    *fetcher.UserInfo
+	metric      FetchMetric
+	err         error
+}
+
+// FetchAll fetches and parses every URL, bounded by the worker pool's
+// concurrency limit, and returns the merged proxies, parse warnings,
+// merged user info, and a per-URL metric regardless of success.
+func (sf *subscriptionFetcher) FetchAll(ctx context.Context, urls []string, proxyOverride string) ([]*proxy.Proxy, []string, *fetcher.UserInfo, []FetchMetric, error) {
+	results := make(chan fetchOutcome, len(urls))
+	var wg sync.WaitGroup
+
+	fetchCtx := apphttp.WithProxyOverride(ctx, proxyOverride)
+
+	for _, u := range urls {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+
+			sf.sem <- struct{}{}
+			defer func() { <-sf.sem }()
+
+			results <- sf.fetchOne(fetchCtx, u)
+		}(u)
+	}
+
+	wg.Wait()
+	close(results)
+
+	var allProxies []*proxy.Proxy
+	var warnings []string
+	var userInfos []*fetcher.UserInfo
+	metrics := make([]FetchMetric, 0, len(urls))
+
+	log := logger.FromContext(ctx, sf.logger)
+	for r := range results {
+		metrics = append(metrics, r.metric)
+		if r.err != nil {
+			log.WithError(r.err).Warn("Failed to process subscription")
+			continue
+		}
+
+		allProxies = append(allProxies, r.proxies...)
+		for _, diag := range r.diagnostics {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", r.metric.URL, diag.Error()))
+		}
+		userInfos = append(userInfos, r.userInfo)
+	}
+
+	if len(allProxies) == 0 {
+		return nil, warnings, nil, metrics, errors.BadRequest("NO_PROXIES", "no valid proxies found in subscriptions")
+	}
+
+	return allProxies, warnings, fetcher.Merge(userInfos...), metrics, nil
+}
+
+// fetchOne rate-limits, fetches (with retry) and parses a single URL,
+// always returning a populated metric.
+func (sf *subscriptionFetcher) fetchOne(ctx context.Context, u string) fetchOutcome {
+	ctx, span := tracer.Start(ctx, "converter.fetchOne", trace.WithAttributes(attribute.String("url", u)))
+	defer span.End()
+
+	start := time.Now()
+	metric := FetchMetric{URL: u}
+
+	if limiter := sf.limiters.forURL(u); limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			metric.Duration = time.Since(start)
+			metric.Error = err.Error()
+			span.RecordError(err)
+			return fetchOutcome{metric: metric, err: err}
+		}
+	}
+
+	// A dedicated group from s.convertGroup: this one coalesces duplicate
+	// *fetches* across concurrent Convert calls, not whole conversions.
+	fetchedAny, err, _ := sf.group.Do(u, func() (interface{}, error) {
+		return sf.fetchWithRetry(ctx, u)
+	})
+
+	metric.Duration = time.Since(start)
+	if err != nil {
+		metric.Error = err.Error()
+		span.RecordError(err)
+		return fetchOutcome{metric: metric, err: errors.Wrap(err, fmt.Sprintf("failed to fetch URL: %s", u))}
+	}
+
+	retried := fetchedAny.(*retryOutcome)
+	metric.Attempts = retried.attempts
+	span.SetAttributes(attribute.Int("attempts", retried.attempts))
+
+	proxies, diagnostics, err := sf.parserManager.Parse(ctx, string(retried.result.Body))
+	if err != nil {
+		metric.Error = err.Error()
+		span.RecordError(err)
+		return fetchOutcome{metric: metric, err: errors.Wrap(err, fmt.Sprintf("failed to parse subscription: %s", u))}
+	}
+
+	metric.Success = true
+	span.SetAttributes(attribute.Int("proxies", len(proxies)))
+	return fetchOutcome{proxies: proxies, diagnostics: diagnostics, userInfo: retried.result.UserInfo, metric: metric}
+}
+
+// retryOutcome records how many attempts fetchWithRetry needed, for
+// FetchMetric.Attempts.
+type retryOutcome struct {
+	result   *fetcher.Result
+	attempts int
+}
+
+// fetchWithRetry fetches u, retrying up to sf.maxRetries times with
+// exponential backoff (sf.retryBaseDelay, doubling each attempt) after
+// any fetch error. Errors are not classified: 4xx responses and other
+// permanent failures are retried the same way as 5xx responses and
+// timeouts.
+func (sf *subscriptionFetcher) fetchWithRetry(ctx context.Context, u string) (*retryOutcome, error) {
+	var lastErr error
+	for attempt := 1; attempt <= sf.maxRetries+1; attempt++ {
+		result, err := sf.fetcher.Fetch(ctx, u)
+		if err == nil {
+			return &retryOutcome{result: result, attempts: attempt}, nil
+		}
+		lastErr = err
+
+		if attempt > sf.maxRetries {
+			break
+		}
+
+		delay := sf.retryBaseDelay * time.Duration(1<<uint(attempt-1))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, lastErr
+}
+
+// hostLimiters lazily creates and caches a token-bucket rate.Limiter per
+// host, so a subscription list spanning many URLs on the same provider
+// doesn't hammer it while different providers stay unaffected.
+type hostLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	rps      float64
+	burst    int
+}
+
+func newHostLimiters(rps float64, burst int) *hostLimiters {
+	return &hostLimiters{limiters: make(map[string]*rate.Limiter), rps: rps, burst: burst}
+}
+
+// forURL returns the shared limiter for rawURL's host, or nil if rate
+// limiting is disabled (rps <= 0) or rawURL has no host to key by.
+func (h *hostLimiters) forURL(rawURL string) *rate.Limiter {
+	if h.rps <= 0 {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	limiter, ok := h.limiters[u.Host]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(h.rps), h.burst)
+		h.limiters[u.Host] = limiter
+	}
+
+	return limiter
+}
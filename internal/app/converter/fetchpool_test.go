@@ -0,0 +1,111 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/subconverter/subconverter-go/internal/app/fetcher"
+	"github.com/subconverter/subconverter-go/internal/app/parser"
+	"github.com/subconverter/subconverter-go/internal/infra/config"
+	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+)
+
+const testSSSubscription = "ss://YWVzLTI1Ni1nY206dGVzdA==@127.0.0.1:8388#Test"
+
+// fakeFetcher fails its first failTimes calls for a given URL, then
+// succeeds, optionally blocking for delay and tracking the peak number
+// of concurrent in-flight calls.
+type fakeFetcher struct {
+	failTimes int32
+	delay     time.Duration
+
+	calls     int32
+	inFlight  int32
+	peak      int32
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, rawURL string) (*fetcher.Result, error) {
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, n) {
+			break
+		}
+	}
+
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+
+	if atomic.AddInt32(&f.calls, 1) <= f.failTimes {
+		return nil, fmt.Errorf("simulated transient failure")
+	}
+
+	return &fetcher.Result{Body: []byte(testSSSubscription)}, nil
+}
+
+func newTestSubscriptionFetcher(f fetcher.Fetcher, cfg config.FetcherConfig) *subscriptionFetcher {
+	log := logger.New(logger.Config{Level: "debug", Format: "text", Output: "stdout"})
+	parserManager := parser.NewManagerWithOptions(log, parser.ParseOptions{CollectDiagnostics: true})
+	return newSubscriptionFetcher(f, parserManager, cfg, log)
+}
+
+func TestSubscriptionFetcher_RetriesTransientFailures(t *testing.T) {
+	fake := &fakeFetcher{failTimes: 2}
+	sf := newTestSubscriptionFetcher(fake, config.FetcherConfig{MaxRetries: 2, RetryBaseDelayMS: 1})
+
+	proxies, _, _, metrics, err := sf.FetchAll(context.Background(), []string{"https://example.com/sub"}, "")
+	require.NoError(t, err)
+	require.Len(t, proxies, 1)
+	require.Len(t, metrics, 1)
+	assert.True(t, metrics[0].Success)
+	assert.Equal(t, 3, metrics[0].Attempts)
+}
+
+func TestSubscriptionFetcher_GivesUpAfterMaxRetries(t *testing.T) {
+	fake := &fakeFetcher{failTimes: 10}
+	sf := newTestSubscriptionFetcher(fake, config.FetcherConfig{MaxRetries: 1, RetryBaseDelayMS: 1})
+
+	_, _, _, metrics, err := sf.FetchAll(context.Background(), []string{"https://example.com/sub"}, "")
+	assert.Error(t, err)
+	require.Len(t, metrics, 1)
+	assert.False(t, metrics[0].Success)
+	assert.NotEmpty(t, metrics[0].Error)
+}
+
+func TestSubscriptionFetcher_BoundsConcurrency(t *testing.T) {
+	fake := &fakeFetcher{delay: 20 * time.Millisecond}
+	sf := newTestSubscriptionFetcher(fake, config.FetcherConfig{MaxConcurrency: 2})
+
+	urls := []string{
+		"https://a.example.com/sub",
+		"https://b.example.com/sub",
+		"https://c.example.com/sub",
+		"https://d.example.com/sub",
+	}
+
+	_, _, _, metrics, err := sf.FetchAll(context.Background(), urls, "")
+	require.NoError(t, err)
+	assert.Len(t, metrics, 4)
+	assert.LessOrEqual(t, atomic.LoadInt32(&fake.peak), int32(2))
+}
+
+func TestHostLimiters_SeparatesHostsAndDisablesWhenUnconfigured(t *testing.T) {
+	disabled := newHostLimiters(0, 0)
+	assert.Nil(t, disabled.forURL("https://example.com/sub"))
+
+	enabled := newHostLimiters(10, 10)
+	a := enabled.forURL("https://a.example.com/sub")
+	b := enabled.forURL("https://b.example.com/sub")
+	require.NotNil(t, a)
+	require.NotNil(t, b)
+	assert.NotSame(t, a, b)
+	assert.Same(t, a, enabled.forURL("https://a.example.com/other"))
+}
@@ -1,6 +1,9 @@
 package converter
 
 import (
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/app/fetcher"
 	"github.com/subconverter/subconverter-go/internal/app/generator"
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
 	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
@@ -11,7 +14,29 @@ type ConvertRequest struct {
 	Target    string         `json:"target" validate:"required"`
 	URLs      []string       `json:"urls" validate:"required,gt=0"`
 	ConfigURL string         `json:"config,omitempty"`
+	UserAgent string         `json:"user_agent,omitempty"`
 	Options   Options        `json:"options,omitempty"`
+	// StrictConfig rejects unknown JSON fields, unknown target/rule types,
+	// and invalid proxy-group configuration instead of silently ignoring
+	// them. See Service.ValidateStrict.
+	StrictConfig bool `json:"strict_config,omitempty"`
+	// ShortLinkToken is set by ResolveShortLink when this request was
+	// loaded from a short link rather than submitted directly, so
+	// generateCacheKey can key by the stable token instead of the URL
+	// list. Not part of the public JSON request.
+	ShortLinkToken string `json:"-"`
+}
+
+// ShortenRequest asks for req to be persisted under a new short-link
+// token.
+type ShortenRequest struct {
+	Request    ConvertRequest `json:"request" validate:"required"`
+	Passphrase string         `json:"passphrase,omitempty"`
+}
+
+// ShortenResponse returns the token a ShortenRequest was stored under.
+type ShortenResponse struct {
+	Token string `json:"token"`
 }
 
 // Options contains conversion options
@@ -20,31 +45,81 @@ type Options struct {
 	ExcludeRemarks []string                `json:"exclude_remarks,omitempty"`
 	RenameRules    []generator.RenameRule  `json:"rename_rules,omitempty"`
 	EmojiRules     []generator.EmojiRule   `json:"emoji_rules,omitempty"`
+	AutoEmoji      bool                    `json:"auto_emoji,omitempty"`
 	Sort           bool                    `json:"sort,omitempty"`
+	SortBy         generator.SortBy        `json:"sort_by,omitempty"`
+	DeduplicateBy  generator.DeduplicateBy `json:"deduplicate_by,omitempty"`
 	UDP            bool                    `json:"udp,omitempty"`
 	ProxyGroups    []generator.ProxyGroup  `json:"proxy_groups,omitempty"`
 	Rules          []string                `json:"rules,omitempty"`
-	CustomOptions  map[string]interface{}  `json:"custom_options,omitempty"`
+	// BaseTemplate names a stored template (see template.Manager) the
+	// Clash generator renders proxies/proxy-groups/rules into, instead of
+	// building a config from scratch.
+	BaseTemplate string `json:"base_template,omitempty"`
+	// Measure runs an active TCP-connect probe (see healthcheck.Prober)
+	// against every proxy before MinRTT/MaxRTT/DropDead/SortByLatency are
+	// applied, populating proxy.Proxy.Latency and proxy.Proxy.Alive.
+	Measure bool `json:"measure,omitempty"`
+	// MinRTT and MaxRTT drop measured proxies outside the range;
+	// requires Measure. Zero disables the corresponding bound.
+	MinRTT time.Duration `json:"min_rtt,omitempty"`
+	MaxRTT time.Duration `json:"max_rtt,omitempty"`
+	// DropDead drops proxies the probe couldn't reach at all; requires
+	// Measure.
+	DropDead bool `json:"drop_dead,omitempty"`
+	// Proxy routes this request's subscription fetches through a
+	// specific upstream proxy (http://, https://, or socks5://),
+	// overriding FetcherConfig.ProxyURL for this request only.
+	Proxy         string                 `json:"proxy,omitempty"`
+	CustomOptions map[string]interface{} `json:"custom_options,omitempty"`
 }
 
 // ConvertResponse represents a conversion response
 type ConvertResponse struct {
-	Config    string              `json:"config"`
-	Format    string              `json:"format"`
-	Proxies   []*proxy.Proxy      `json:"proxies"`
-	RuleSets  []*ruleset.RuleSet  `json:"rule_sets,omitempty"`
-	Generated string              `json:"generated"`
+	Config           string             `json:"config"`
+	Format           string             `json:"format"`
+	Proxies          []*proxy.Proxy     `json:"proxies"`
+	RuleSets         []*ruleset.RuleSet `json:"rule_sets,omitempty"`
+	Generated        string             `json:"generated"`
+	ParseWarnings    []string           `json:"parse_warnings,omitempty"`
+	SubscriptionInfo *fetcher.UserInfo  `json:"subscription_info,omitempty"`
+	FetchMetrics     []FetchMetric      `json:"fetch_metrics,omitempty"`
+}
+
+// FetchMetric reports how fetching a single subscription URL went, so
+// callers can tell which providers are slow or flaky without grepping
+// server logs.
+type FetchMetric struct {
+	URL      string        `json:"url"`
+	Success  bool          `json:"success"`
+	Attempts int           `json:"attempts"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
 }
 
 // BatchConvertRequest represents a batch conversion request
 type BatchConvertRequest struct {
 	Requests []ConvertRequest `json:"requests" validate:"required,gt=0"`
+	// FailFast cancels every in-flight conversion as soon as one request
+	// fails with a non-validation error, instead of letting the rest finish.
+	FailFast bool `json:"fail_fast,omitempty"`
 }
 
 // BatchConvertResponse represents a batch conversion response
 type BatchConvertResponse struct {
 	Results []ConvertResponse `json:"results"`
-	Errors  []string          `json:"errors,omitempty"`
+	Errors  []BatchError      `json:"errors,omitempty"`
+}
+
+// BatchError reports why a single request within a batch failed, keeping
+// the original error code/status so callers can programmatically react
+// (e.g. retry only requests whose Code indicates a transient failure).
+type BatchError struct {
+	Index   int    `json:"index"`
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Cause   string `json:"cause,omitempty"`
 }
 
 // ValidateRequest represents a validation request
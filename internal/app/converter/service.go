@@ -2,56 +2,291 @@ package converter
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/samber/lo"
+	"github.com/subconverter/subconverter-go/internal/api/clash"
+	"github.com/subconverter/subconverter-go/internal/app/adapter"
+	"github.com/subconverter/subconverter-go/internal/app/fetcher"
 	"github.com/subconverter/subconverter-go/internal/app/generator"
+	"github.com/subconverter/subconverter-go/internal/app/geodata"
+	"github.com/subconverter/subconverter-go/internal/app/healthcheck"
 	"github.com/subconverter/subconverter-go/internal/app/parser"
+	"github.com/subconverter/subconverter-go/internal/app/ruleprovider"
+	"github.com/subconverter/subconverter-go/internal/app/shortener"
 	"github.com/subconverter/subconverter-go/internal/app/template"
+	"github.com/subconverter/subconverter-go/internal/dns"
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
 	"github.com/subconverter/subconverter-go/internal/infra/cache"
 	"github.com/subconverter/subconverter-go/internal/infra/config"
 	"github.com/subconverter/subconverter-go/internal/infra/http"
 	"github.com/subconverter/subconverter-go/internal/pkg/errors"
 	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/multierr"
+	"golang.org/x/sync/singleflight"
 )
 
+// tracer emits spans around each stage of Service.Convert (fetch, parse,
+// filter, generate, cache) so a request can be traced end to end.
+var tracer = otel.Tracer("github.com/subconverter/subconverter-go/internal/app/converter")
+
 // Service provides the core conversion functionality
 type Service struct {
-	parserManager    *parser.Manager
-	generatorManager *generator.Manager
-	templateManager  *template.Manager
-	cache            cache.Cache
-	config           *config.Config
-	httpClient       *http.Client
-	logger           *logger.Logger
+	parserManager     *parser.Manager
+	generatorManager  *generator.Manager
+	templateManager   *template.Manager
+	templateStore     cache.Cache
+	cache             cache.Cache
+	config            *config.Config
+	httpClient        *http.Client
+	shortLinkResolver http.ShortLinkResolver
+	fetcher           fetcher.Fetcher
+	subFetcher        *subscriptionFetcher
+	ruleProviders     *ruleprovider.Store
+	geoCompiler       *geodata.Compiler
+	prober            *healthcheck.Prober
+	trafficController adapter.TrafficController
+	clashRegistry     *clash.Registry
+	clashController   *clash.Controller
+	shortener         *shortener.Service
+	shortLinkStore    cache.Cache
+	logger            *logger.Logger
+	convertGroup      singleflight.Group
 }
 
 // NewService creates a new conversion service
 func NewService(cfg *config.Config, log *logger.Logger) *Service {
-	templateManager := template.NewManager(cfg.Generator.TemplatesDir, cfg.Generator.RulesDir, *log)
+	var templateStore cache.Cache
+	boltStore, err := cache.NewBoltCache(cfg.Generator.CacheDB)
+	if err != nil {
+		log.WithError(err).Warn("failed to open persistent template store, falling back to in-memory cache")
+		templateStore = cache.NewMemoryCache()
+	} else {
+		templateStore = boltStore
+	}
+
+	templateManager := template.NewManager(
+		cfg.Generator.TemplatesDir,
+		cfg.Generator.RulesDir,
+		templateStore,
+		time.Duration(cfg.Cache.TTL)*time.Second,
+		*log,
+	)
+
+	httpClient, err := http.NewClient(http.ProxyConfig{
+		URL:         cfg.Fetcher.ProxyURL,
+		BearerToken: cfg.Fetcher.ProxyBearerToken,
+		AuthFile:    cfg.Fetcher.ProxyAuthFile,
+	})
+	if err != nil {
+		log.WithError(err).Warn("failed to configure upstream proxy, falling back to a direct connection")
+		httpClient, _ = http.NewClient(http.ProxyConfig{})
+	}
+
+	shortLinkResolver := http.NewDefaultShortLinkResolver(
+		cfg.Security.ShortLink,
+		time.Duration(cfg.Parser.Timeout)*time.Second,
+	)
+
+	responseCache, err := cache.New(cfg)
+	if err != nil {
+		log.WithError(err).Warn("failed to open configured response cache backend, falling back to in-memory cache")
+		responseCache = cache.NewMemoryCache()
+	}
+
+	var shortLinkStore cache.Cache
+	boltShortLinkStore, err := cache.NewBoltCache(cfg.Shortener.BoltPath)
+	if err != nil {
+		log.WithError(err).Warn("failed to open persistent short-link store, falling back to in-memory cache")
+		shortLinkStore = cache.NewMemoryCache()
+	} else {
+		shortLinkStore = boltShortLinkStore
+	}
+
+	parserManager := parser.NewManagerWithOptions(log, parser.ParseOptions{
+		Strict:             cfg.Parser.Strict,
+		MaxErrors:          cfg.Parser.MaxErrors,
+		CollectDiagnostics: true,
+	})
+
+	defaultFetcher := fetcher.NewDefaultFetcher(
+		shortLinkResolver,
+		httpClient,
+		cache.NewMemoryCache(),
+		cfg.Fetcher,
+		time.Duration(cfg.Cache.TTL)*time.Second,
+	)
+
+	prober := healthcheck.NewProber(
+		cache.NewMemoryCache(),
+		time.Duration(cfg.Probe.TTL)*time.Second,
+		time.Duration(cfg.Probe.TimeoutMS)*time.Millisecond,
+		cfg.Probe.MaxConcurrency,
+	)
+
+	trafficController := adapter.NewController()
+	prober.SetTrafficController(trafficController)
+
+	if cfg.DNS.Enabled {
+		resolver := newDNSResolver(cfg.DNS)
+		httpClient.SetResolver(resolver)
+		prober.SetResolver(resolver)
+	}
+
+	var clashRegistry *clash.Registry
+	var clashController *clash.Controller
+	if cfg.Clash.Enabled {
+		clashRegistry = clash.NewRegistry(prober, trafficController)
+		logHook := clash.NewLogHook()
+		log.AddHook(logHook)
+		clashController = clash.NewController(clashRegistry, logHook, cfg.Clash.Secret)
+	}
 
 	return &Service{
-		parserManager:    parser.NewManager(log),
-		generatorManager: generator.NewManager(),
-		templateManager:  templateManager,
-		cache:            cache.NewMemoryCache(),
-		config:           cfg,
-		httpClient:       http.NewClient(),
-		logger:           log,
+		parserManager:     parserManager,
+		generatorManager:  generator.NewManager(),
+		templateManager:   templateManager,
+		templateStore:     templateStore,
+		cache:             cache.NewCompressed(responseCache),
+		config:            cfg,
+		httpClient:        httpClient,
+		shortLinkResolver: shortLinkResolver,
+		fetcher:           defaultFetcher,
+		subFetcher:        newSubscriptionFetcher(defaultFetcher, parserManager, cfg.Fetcher, log),
+		ruleProviders: ruleprovider.NewStore(
+			httpClient,
+			cache.NewMemoryCache(),
+			time.Duration(cfg.Cache.TTL)*time.Second,
+			log,
+		),
+		geoCompiler:       geodata.NewCompiler(),
+		prober:            prober,
+		trafficController: trafficController,
+		clashRegistry:     clashRegistry,
+		clashController:   clashController,
+		shortener:         shortener.NewService(shortLinkStore, time.Duration(cfg.Shortener.TTL)*time.Second),
+		shortLinkStore:    shortLinkStore,
+		logger:            log,
 	}
 }
 
+// newDNSResolver builds the DoH -> system -> static fallback chain
+// cfg.DNS describes: DoH is tried first, falling back to the OS resolver
+// and then cfg.DNS.StaticHosts when DoH fails or a host has no record.
+// The DoH upstream's own hostname is resolved through a
+// static-then-system chain, never through the DoHResolver itself, so
+// bootstrapping it can't depend on DoH being reachable yet.
+func newDNSResolver(cfg config.DNSConfig) dns.Resolver {
+	static := dns.NewStaticResolver(cfg.StaticHosts)
+	system := dns.NewSystemResolver()
+	bootstrap := dns.NewChain(static, system)
+
+	doh := dns.NewDoHResolver(
+		cfg.Upstream,
+		bootstrap,
+		cache.NewMemoryCache(),
+		time.Duration(cfg.TTL)*time.Second,
+		net.ParseIP(cfg.ClientSubnet),
+	)
+
+	return dns.NewChain(doh, system, static)
+}
+
+// ClashController returns the Clash-compatible external controller for
+// this service, or nil if cfg.Clash.Enabled is false. Router checks this
+// before mounting clash routes.
+func (s *Service) ClashController() *clash.Controller {
+	return s.clashController
+}
+
+// TrafficController returns the traffic controller every outbound
+// connection dialed for a proxy.Proxy is routed through, so its
+// per-proxy byte counts can be attributed back via GET /connections.
+func (s *Service) TrafficController() adapter.TrafficController {
+	return s.trafficController
+}
+
+// RuleProviders returns the store tracking remote rule-provider bodies,
+// used by the /providers/:name endpoint and kept warm by
+// StartRuleProviderRefresh.
+func (s *Service) RuleProviders() *ruleprovider.Store {
+	return s.ruleProviders
+}
+
+// GeoCompiler returns the compiler the V2Ray generator uses to inline
+// GEOIP/GEOSITE rules. Callers load it with LoadGeoIPFile/
+// LoadGeoSiteFile/LoadGeoSiteRemote once at startup; an unloaded compiler
+// leaves GEOIP/GEOSITE rules in V2Ray's native "geoip:"/"geosite:" tag
+// form.
+func (s *Service) GeoCompiler() *geodata.Compiler {
+	return s.geoCompiler
+}
+
+// RegisterRuleProvider records a named remote rule list so it can be
+// served from /providers/:name and kept warm by StartRuleProviderRefresh.
+func (s *Service) RegisterRuleProvider(name string, provider *ruleset.RuleProvider) {
+	s.ruleProviders.Register(name, provider)
+}
+
+// StartRuleProviderRefresh periodically re-fetches every registered rule
+// provider until ctx is cancelled.
+func (s *Service) StartRuleProviderRefresh(ctx context.Context, interval time.Duration) {
+	s.ruleProviders.StartBackgroundRefresh(ctx, interval)
+}
+
+// CreateShortLink persists req under a new opaque token, optionally
+// requiring passphrase to resolve it, and returns the token.
+func (s *Service) CreateShortLink(ctx context.Context, req *ConvertRequest, passphrase string) (string, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode request for short link")
+	}
+
+	return s.shortener.Create(ctx, payload, passphrase)
+}
+
+// ResolveShortLink returns the ConvertRequest stored under token, with
+// ShortLinkToken set so Convert's cache key is keyed by token rather than
+// the request's URL list.
+func (s *Service) ResolveShortLink(ctx context.Context, token, passphrase string) (*ConvertRequest, error) {
+	payload, err := s.shortener.Resolve(ctx, token, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var req ConvertRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, errors.Wrap(err, "failed to decode short-linked request")
+	}
+	req.ShortLinkToken = token
+
+	return &req, nil
+}
+
 // Convert converts subscription URLs to target format
 func (s *Service) Convert(ctx context.Context, req *ConvertRequest) (*ConvertResponse, error) {
+	ctx, span := tracer.Start(ctx, "converter.Convert", trace.WithAttributes(
+		attribute.String("target", req.Target),
+		attribute.Int("urls", len(req.URLs)),
+	))
+	defer span.End()
+
+	log := logger.FromContext(ctx, s.logger)
 	start := time.Now()
 	defer func() {
-		s.logger.WithFields(map[string]interface{}{
+		log.WithFields(map[string]interface{}{
 			"target":   req.Target,
 			"urls":     len(req.URLs),
 			"duration": time.Since(start),
@@ -63,44 +298,78 @@ func (s *Service) Convert(ctx context.Context, req *ConvertRequest) (*ConvertRes
 		return nil, err
 	}
 
+	if req.UserAgent != "" {
+		ctx = context.WithValue(ctx, http.UserAgentKey, req.UserAgent)
+	}
+
 	// Check cache
 	cacheKey := s.generateCacheKey(req)
 	if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
 		var resp ConvertResponse
 		if err := json.Unmarshal(cached, &resp); err == nil {
+			span.SetAttributes(attribute.Bool("cache.hit", true), attribute.Int("proxies", len(resp.Proxies)))
+			if s.clashRegistry != nil {
+				s.clashRegistry.Update(resp.Proxies, s.buildProxyGroups(req.Options), req.Options.Rules, resp.SubscriptionInfo)
+			}
 			return &resp, nil
 		}
 	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
 
+	// Coalesce concurrent requests for the same cache key so that only one
+	// of them fetches, parses and generates while the rest wait on its result.
+	respAny, err, _ := s.convertGroup.Do(cacheKey, func() (interface{}, error) {
+		return s.doConvert(ctx, req, cacheKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := respAny.(*ConvertResponse)
+	span.SetAttributes(attribute.Int("proxies", len(resp.Proxies)))
+	return resp, nil
+}
+
+func (s *Service) doConvert(ctx context.Context, req *ConvertRequest, cacheKey string) (*ConvertResponse, error) {
 	// Fetch subscriptions
-	allProxies, err := s.fetchSubscriptions(ctx, req.URLs)
+	fetchCtx, fetchSpan := tracer.Start(ctx, "converter.fetchSubscriptions")
+	allProxies, parseWarnings, userInfo, fetchMetrics, err := s.fetchSubscriptions(fetchCtx, req.URLs, req.Options.Proxy)
+	fetchSpan.SetAttributes(attribute.Int("proxies.fetched", len(allProxies)))
+	fetchSpan.End()
 	if err != nil {
 		return nil, err
 	}
 
 	// Apply filters
-	filteredProxies := s.applyFilters(allProxies, req.Options)
+	filterCtx, filterSpan := tracer.Start(ctx, "converter.applyFilters")
+	filteredProxies := s.applyFilters(filterCtx, allProxies, req.Options)
+	filterSpan.SetAttributes(attribute.Int("proxies.filtered", len(filteredProxies)))
+	filterSpan.End()
 
 	// Generate configuration
-	config, err := s.generatorManager.Generate(ctx, req.Target, filteredProxies, nil, generator.GenerateOptions{
-		ProxyGroups:  s.buildProxyGroups(req.Options),
+	genCtx, genSpan := tracer.Start(ctx, "converter.generate", trace.WithAttributes(attribute.String("target", req.Target)))
+	groups := s.buildProxyGroups(req.Options)
+	config, err := s.generatorManager.Generate(genCtx, req.Target, filteredProxies, nil, generator.GenerateOptions{
+		ProxyGroups:  groups,
 		Rules:        req.Options.Rules,
 		SortProxies:  req.Options.Sort,
 		UDPEnabled:   req.Options.UDP,
-		RenameRules:  req.Options.RenameRules,
-		EmojiRules:   req.Options.EmojiRules,
 		BaseTemplate: req.Options.BaseTemplate,
 	})
+	genSpan.End()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to generate configuration")
 	}
 
 	// Build response
 	resp := &ConvertResponse{
-		Config:    config,
-		Format:    req.Target,
-		Proxies:   filteredProxies,
-		Generated: time.Now().Format(time.RFC3339),
+		Config:           config,
+		Format:           req.Target,
+		Proxies:          filteredProxies,
+		Generated:        time.Now().Format(time.RFC3339),
+		ParseWarnings:    parseWarnings,
+		SubscriptionInfo: userInfo,
+		FetchMetrics:     fetchMetrics,
 	}
 
 	// Cache the response
@@ -108,12 +377,16 @@ func (s *Service) Convert(ctx context.Context, req *ConvertRequest) (*ConvertRes
 		s.cache.Set(ctx, cacheKey, cacheData, time.Duration(s.config.Cache.TTL)*time.Second)
 	}
 
+	if s.clashRegistry != nil {
+		s.clashRegistry.Update(filteredProxies, groups, req.Options.Rules, userInfo)
+	}
+
 	return resp, nil
 }
 
 // Validate validates a subscription URL
 func (s *Service) Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
-	content, err := s.httpClient.Get(ctx, req.URL)
+	fetched, err := s.fetcher.Fetch(ctx, req.URL)
 	if err != nil {
 		return &ValidateResponse{
 			Valid: false,
@@ -121,7 +394,7 @@ func (s *Service) Validate(ctx context.Context, req *ValidateRequest) (*Validate
 		}, nil
 	}
 
-	proxies, err := s.parserManager.Parse(ctx, string(content))
+	proxies, _, err := s.parserManager.Parse(ctx, string(fetched.Body))
 	if err != nil {
 		return &ValidateResponse{
 			Valid: false,
@@ -129,7 +402,7 @@ func (s *Service) Validate(ctx context.Context, req *ValidateRequest) (*Validate
 		}, nil
 	}
 
-	format := s.detectFormat(string(content))
+	format := s.detectFormat(string(fetched.Body))
 
 	return &ValidateResponse{
 		Valid:   true,
@@ -196,6 +469,20 @@ func (s *Service) Health(ctx context.Context) error {
 	return nil
 }
 
+// Close releases resources held by the service, such as the persistent
+// template store's file handle. Call it during graceful shutdown.
+func (s *Service) Close() error {
+	var err error
+	if closer, ok := s.templateStore.(io.Closer); ok {
+		err = multierr.Append(err, closer.Close())
+	}
+	if closer, ok := s.shortLinkStore.(io.Closer); ok {
+		err = multierr.Append(err, closer.Close())
+	}
+
+	return err
+}
+
 func (s *Service) validateRequest(req *ConvertRequest) error {
 	if req.Target == "" {
 		return errors.BadRequest("INVALID_TARGET", "target format is required")
@@ -213,57 +500,15 @@ func (s *Service) validateRequest(req *ConvertRequest) error {
 	return nil
 }
 
-func (s *Service) fetchSubscriptions(ctx context.Context, urls []string) ([]*proxy.Proxy, error) {
-	type result struct {
-		proxies []*proxy.Proxy
-		err     error
-	}
-
-	results := make(chan result, len(urls))
-	var wg sync.WaitGroup
-
-	for _, url := range urls {
-		wg.Add(1)
-		go func(u string) {
-			defer wg.Done()
-
-			content, err := s.httpClient.Get(ctx, u)
-			if err != nil {
-				results <- result{err: errors.Wrap(err, fmt.Sprintf("failed to fetch URL: %s", u))}
-				return
-			}
-
-			proxies, err := s.parserManager.Parse(ctx, string(content))
-			if err != nil {
-				results <- result{err: errors.Wrap(err, fmt.Sprintf("failed to parse subscription: %s", u))}
-				return
-			}
-
-			results <- result{proxies: proxies}
-		}(url)
-	}
-
-	wg.Wait()
-	close(results)
-
-	// Collect results
-	var allProxies []*proxy.Proxy
-	for r := range results {
-		if r.err != nil {
-			s.logger.WithError(r.err).Warn("Failed to process subscription")
-			continue
-		}
-		allProxies = append(allProxies, r.proxies...)
-	}
-
-	if len(allProxies) == 0 {
-		return nil, errors.BadRequest("NO_PROXIES", "no valid proxies found in subscriptions")
-	}
-
-	return allProxies, nil
+// fetchSubscriptions fetches and parses every subscription URL through
+// s.subFetcher, which bounds concurrency, rate-limits per host, retries
+// transient failures, and coalesces duplicate URLs across concurrent
+// Convert calls.
+func (s *Service) fetchSubscriptions(ctx context.Context, urls []string, proxyOverride string) ([]*proxy.Proxy, []string, *fetcher.UserInfo, []FetchMetric, error) {
+	return s.subFetcher.FetchAll(ctx, urls, proxyOverride)
 }
 
-func (s *Service) applyFilters(proxies []*proxy.Proxy, options Options) []*proxy.Proxy {
+func (s *Service) applyFilters(ctx context.Context, proxies []*proxy.Proxy, options Options) []*proxy.Proxy {
 	result := proxies
 
 	// Apply include filters
@@ -284,45 +529,42 @@ func (s *Service) applyFilters(proxies []*proxy.Proxy, options Options) []*proxy
 		})
 	}
 
-	// Apply rename rules
-	if len(options.RenameRules) > 0 {
-		for _, p := range result {
-			for _, rule := range options.RenameRules {
-				p.Name = strings.ReplaceAll(p.Name, rule.Match, rule.Replace)
+	// Active health check: populate Latency/Alive, then drop proxies
+	// outside the requested RTT window or that didn't answer at all.
+	if options.Measure {
+		_, measureSpan := tracer.Start(ctx, "converter.measure")
+		s.prober.Measure(ctx, result)
+		result = lo.Filter(result, func(p *proxy.Proxy, _ int) bool {
+			if options.DropDead && !p.Alive {
+				return false
 			}
-		}
-	}
-
-	// Apply emoji rules
-	if len(options.EmojiRules) > 0 {
-		for _, p := range result {
-			for _, rule := range options.EmojiRules {
-				if strings.Contains(p.Name, rule.Match) {
-					p.Name = rule.Emoji + " " + p.Name
-				}
+			if options.MinRTT > 0 && p.Alive && time.Duration(p.Latency)*time.Millisecond < options.MinRTT {
+				return false
 			}
-		}
-	}
-
-	// Sort proxies
-	if options.Sort {
-		sort.Slice(result, func(i, j int) bool {
-			return result[i].Name < result[j].Name
+			if options.MaxRTT > 0 && p.Alive && time.Duration(p.Latency)*time.Millisecond > options.MaxRTT {
+				return false
+			}
+			return true
 		})
+		measureSpan.SetAttributes(attribute.Int("proxies.measured", len(result)))
+		measureSpan.End()
 	}
 
-	// Remove duplicates
-	seen := make(map[string]bool)
-	unique := make([]*proxy.Proxy, 0, len(result))
-	for _, p := range result {
-		key := fmt.Sprintf("%s:%d:%s", p.Server, p.Port, p.Type)
-		if !seen[key] {
-			seen[key] = true
-			unique = append(unique, p)
-		}
+	// Rename, emoji-tag, deduplicate, and sort via the shared NameProcessor
+	// so every generator sees the same final names.
+	sortBy := options.SortBy
+	if sortBy == "" && options.Sort {
+		sortBy = generator.SortByName
+	}
+	processor := &generator.NameProcessor{
+		RenameRules:   options.RenameRules,
+		EmojiRules:    options.EmojiRules,
+		AutoEmoji:     options.AutoEmoji,
+		SortBy:        sortBy,
+		DeduplicateBy: options.DeduplicateBy,
 	}
 
-	return unique
+	return processor.Process(result)
 }
 
 func (s *Service) buildProxyGroups(options Options) []generator.ProxyGroup {
@@ -355,21 +597,41 @@ func (s *Service) buildProxyGroups(options Options) []generator.ProxyGroup {
 }
 
 func (s *Service) generateCacheKey(req *ConvertRequest) string {
+	if req.ShortLinkToken != "" {
+		return fmt.Sprintf("convert:token:%s:%s", req.Target, req.ShortLinkToken)
+	}
+
 	urls := make([]string, len(req.URLs))
 	copy(urls, req.URLs)
 	sort.Strings(urls)
-	key := fmt.Sprintf("convert:%s:%s", req.Target, strings.Join(urls, ","))
+	key := fmt.Sprintf("convert:%s:%s:%s", req.Target, strings.Join(urls, ","), hashOptions(req.Options))
 	return key
 }
 
+// hashOptions produces a stable cache-key fragment for req.Options, the
+// same way template.Manager's hashRenderData hashes render data, so two
+// requests with identical URLs/Target but different proxy groups,
+// rename/emoji rules, filters, or other Options don't collide in
+// convertGroup or the response cache.
+func hashOptions(opts Options) string {
+	encoded, err := json.Marshal(opts)
+	if err != nil {
+		return fmt.Sprintf("%v", opts)
+	}
+
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
 // RegisterGenerators registers all available generators
 func (s *Service) RegisterGenerators() {
 	s.generatorManager.Register("clash", generator.NewClashGenerator(s.templateManager))
 	s.generatorManager.Register("surge", generator.NewSurgeGenerator())
 	s.generatorManager.Register("quantumult", generator.NewQuantumultGenerator())
 	s.generatorManager.Register("loon", generator.NewLoonGenerator())
-	s.generatorManager.Register("v2ray", generator.NewV2RayGenerator())
+	s.generatorManager.Register("v2ray", generator.NewV2RayGenerator(s.geoCompiler))
 	s.generatorManager.Register("surfboard", generator.NewSurfboardGenerator())
+	s.generatorManager.Register("sing-box", generator.NewSingBoxGenerator())
 }
 
 // SupportedFormats returns all supported formats
@@ -381,6 +643,10 @@ func (s *Service) HTTPClient() *http.Client {
 	return s.httpClient
 }
 
+func (s *Service) Logger() *logger.Logger {
+	return s.logger
+}
+
 func (s *Service) ParserManager() *parser.Manager {
 	return s.parserManager
 }
@@ -3,10 +3,14 @@ package converter
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/subconverter/subconverter-go/internal/app/generator"
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
 	"github.com/subconverter/subconverter-go/internal/infra/config"
 	"github.com/subconverter/subconverter-go/internal/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestService_Convert(t *testing.T) {
@@ -98,6 +102,7 @@ func TestService_SupportedFormats(t *testing.T) {
 	assert.Contains(t, formats, "loon")
 	assert.Contains(t, formats, "v2ray")
 	assert.Contains(t, formats, "surfboard")
+	assert.Contains(t, formats, "sing-box")
 }
 
 func TestService_Health(t *testing.T) {
@@ -113,6 +118,112 @@ func TestService_Health(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestService_ShortLinkRoundTrip(t *testing.T) {
+	cfg := &config.Config{}
+	log := logger.New(logger.Config{
+		Level:  "debug",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	service := NewService(cfg, log)
+
+	req := &ConvertRequest{
+		Target: "clash",
+		URLs:   []string{"https://example.com/subscription"},
+	}
+
+	token, err := service.CreateShortLink(context.Background(), req, "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	resolved, err := service.ResolveShortLink(context.Background(), token, "")
+	assert.NoError(t, err)
+	assert.Equal(t, req.Target, resolved.Target)
+	assert.Equal(t, req.URLs, resolved.URLs)
+	assert.Equal(t, token, resolved.ShortLinkToken)
+
+	assert.Equal(t, "convert:token:clash:"+token, service.generateCacheKey(resolved))
+}
+
+// TestService_GenerateCacheKey_DiffersByOptions guards against a
+// response-cache-backend regression: two requests sharing Target and URLs
+// but differing Options (here, a proxy group) must not hash to the same
+// key, or the persistent cache (Redis/Bolt) would serve one request's
+// config to the other.
+func TestService_GenerateCacheKey_DiffersByOptions(t *testing.T) {
+	cfg := &config.Config{}
+	log := logger.New(logger.Config{
+		Level:  "debug",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	service := NewService(cfg, log)
+
+	base := &ConvertRequest{
+		Target: "clash",
+		URLs:   []string{"https://example.com/subscription"},
+	}
+	withGroups := &ConvertRequest{
+		Target:  "clash",
+		URLs:    []string{"https://example.com/subscription"},
+		Options: Options{ProxyGroups: []generator.ProxyGroup{{Name: "Auto", Type: "select"}}},
+	}
+
+	assert.NotEqual(t, service.generateCacheKey(base), service.generateCacheKey(withGroups))
+}
+
+// TestService_ApplyFilters_MinRTTKeepsDeadProxyWithoutDropDead guards
+// against MinRTT reimplementing dead-proxy removal on its own: a proxy
+// the probe couldn't reach at all has Latency 0, which satisfies "<
+// MinRTT" for any positive MinRTT, but dropping it is DropDead's job.
+func TestService_ApplyFilters_MinRTTKeepsDeadProxyWithoutDropDead(t *testing.T) {
+	cfg := &config.Config{
+		Probe: config.ProbeConfig{TimeoutMS: 50},
+	}
+	log := logger.New(logger.Config{
+		Level:  "debug",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	service := NewService(cfg, log)
+
+	proxies := []*proxy.Proxy{
+		{ID: "unreachable", Server: "127.0.0.1", Port: 1},
+	}
+
+	filtered := service.applyFilters(context.Background(), proxies, Options{
+		Measure: true,
+		MinRTT:  time.Second,
+	})
+
+	require.Len(t, filtered, 1)
+	assert.False(t, filtered[0].Alive)
+}
+
+func TestService_ShortLinkRequiresPassphrase(t *testing.T) {
+	cfg := &config.Config{}
+	log := logger.New(logger.Config{
+		Level:  "debug",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	service := NewService(cfg, log)
+
+	req := &ConvertRequest{Target: "clash", URLs: []string{"https://example.com/subscription"}}
+	token, err := service.CreateShortLink(context.Background(), req, "secret")
+	assert.NoError(t, err)
+
+	_, err = service.ResolveShortLink(context.Background(), token, "wrong")
+	assert.Error(t, err)
+
+	_, err = service.ResolveShortLink(context.Background(), token, "secret")
+	assert.NoError(t, err)
+}
+
 func TestService_GetInfo(t *testing.T) {
 	cfg := &config.Config{}
 	log := logger.New(logger.Config{
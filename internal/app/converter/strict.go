@@ -0,0 +1,95 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+)
+
+// knownRuleTypes are the RuleType values a custom "options.rules" line may
+// start with; anything else is rejected under StrictConfig.
+var knownRuleTypes = map[ruleset.RuleType]bool{
+	ruleset.RuleTypeDomain:        true,
+	ruleset.RuleTypeDomainSuffix:  true,
+	ruleset.RuleTypeDomainKeyword: true,
+	ruleset.RuleTypeIPCIDR:        true,
+	ruleset.RuleTypeIPCIDR6:       true,
+	ruleset.RuleTypeGeoIP:         true,
+	ruleset.RuleTypeGeoSite:       true,
+	ruleset.RuleTypeProcessName:   true,
+	ruleset.RuleTypeUserAgent:     true,
+	ruleset.RuleTypeURLRegex:      true,
+	ruleset.RuleTypeFinal:         true,
+	ruleset.RuleTypeMatch:         true,
+}
+
+// ValidateStrict runs the extra checks StrictConfig enables beyond the
+// baseline struct validation: unknown target formats, malformed regex
+// filters, proxy groups missing the fields their type requires, and
+// custom rule lines with an unrecognized rule type. Unlike validator.Validate, which
+// returns the first problem found, this collects every failure so callers
+// can report them all at once.
+func (s *Service) ValidateStrict(req *ConvertRequest) []errors.ValidationFieldError {
+	var fieldErrors []errors.ValidationFieldError
+
+	if _, exists := s.generatorManager.Get(req.Target); !exists {
+		fieldErrors = append(fieldErrors, errors.ValidationFieldError{
+			Field:  "target",
+			Path:   "target",
+			Reason: fmt.Sprintf("unknown target format: %s", req.Target),
+		})
+	}
+
+	fieldErrors = append(fieldErrors, validateRegexFilters("include_remarks", req.Options.IncludeRemarks)...)
+	fieldErrors = append(fieldErrors, validateRegexFilters("exclude_remarks", req.Options.ExcludeRemarks)...)
+
+	for i, group := range req.Options.ProxyGroups {
+		if group.Type != "url-test" && group.Type != "fallback" {
+			continue
+		}
+		if group.URL == "" {
+			fieldErrors = append(fieldErrors, errors.ValidationFieldError{
+				Field:  "url",
+				Path:   fmt.Sprintf("options.proxy_groups[%d].url", i),
+				Reason: fmt.Sprintf("%s proxy group requires a non-empty url", group.Type),
+			})
+		}
+		if group.Interval <= 0 {
+			fieldErrors = append(fieldErrors, errors.ValidationFieldError{
+				Field:  "interval",
+				Path:   fmt.Sprintf("options.proxy_groups[%d].interval", i),
+				Reason: fmt.Sprintf("%s proxy group requires a positive interval", group.Type),
+			})
+		}
+	}
+
+	for i, rule := range req.Options.Rules {
+		ruleType := ruleset.RuleType(strings.SplitN(rule, ",", 2)[0])
+		if !knownRuleTypes[ruleType] {
+			fieldErrors = append(fieldErrors, errors.ValidationFieldError{
+				Field:  "rules",
+				Path:   fmt.Sprintf("options.rules[%d]", i),
+				Reason: fmt.Sprintf("unknown rule type: %s", ruleType),
+			})
+		}
+	}
+
+	return fieldErrors
+}
+
+func validateRegexFilters(field string, patterns []string) []errors.ValidationFieldError {
+	var fieldErrors []errors.ValidationFieldError
+	for i, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			fieldErrors = append(fieldErrors, errors.ValidationFieldError{
+				Field:  field,
+				Path:   fmt.Sprintf("options.%s[%d]", field, i),
+				Reason: fmt.Sprintf("invalid regex: %v", err),
+			})
+		}
+	}
+	return fieldErrors
+}
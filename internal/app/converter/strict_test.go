@@ -0,0 +1,89 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/subconverter/subconverter-go/internal/app/generator"
+	"github.com/subconverter/subconverter-go/internal/infra/config"
+	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStrictTestService() *Service {
+	cfg := &config.Config{Cache: config.CacheConfig{TTL: 300}}
+	log := logger.New(logger.Config{Level: "error", Format: "text", Output: "stdout"})
+	service := NewService(cfg, log)
+	service.RegisterGenerators()
+	return service
+}
+
+func TestService_ValidateStrict_ValidRequest(t *testing.T) {
+	service := newStrictTestService()
+
+	req := &ConvertRequest{
+		Target: "clash",
+		URLs:   []string{"https://example.com/sub"},
+		Options: Options{
+			IncludeRemarks: []string{"^HK"},
+			Rules:          []string{"DOMAIN-SUFFIX,example.com,DIRECT"},
+		},
+	}
+
+	assert.Empty(t, service.ValidateStrict(req))
+}
+
+func TestService_ValidateStrict_UnknownTarget(t *testing.T) {
+	service := newStrictTestService()
+
+	req := &ConvertRequest{Target: "not-a-format"}
+
+	fieldErrors := service.ValidateStrict(req)
+	assert.Len(t, fieldErrors, 1)
+	assert.Equal(t, "target", fieldErrors[0].Field)
+}
+
+func TestService_ValidateStrict_InvalidRegex(t *testing.T) {
+	service := newStrictTestService()
+
+	req := &ConvertRequest{
+		Target: "clash",
+		Options: Options{
+			IncludeRemarks: []string{"("},
+		},
+	}
+
+	fieldErrors := service.ValidateStrict(req)
+	assert.Len(t, fieldErrors, 1)
+	assert.Equal(t, "include_remarks", fieldErrors[0].Field)
+}
+
+func TestService_ValidateStrict_ProxyGroupMissingURLAndInterval(t *testing.T) {
+	service := newStrictTestService()
+
+	req := &ConvertRequest{
+		Target: "clash",
+		Options: Options{
+			ProxyGroups: []generator.ProxyGroup{
+				{Name: "Auto", Type: "url-test"},
+			},
+		},
+	}
+
+	fieldErrors := service.ValidateStrict(req)
+	assert.Len(t, fieldErrors, 2)
+}
+
+func TestService_ValidateStrict_UnknownRuleType(t *testing.T) {
+	service := newStrictTestService()
+
+	req := &ConvertRequest{
+		Target: "clash",
+		Options: Options{
+			Rules: []string{"NOT-A-RULE-TYPE,example.com,DIRECT"},
+		},
+	}
+
+	fieldErrors := service.ValidateStrict(req)
+	assert.Len(t, fieldErrors, 1)
+	assert.Equal(t, "rules", fieldErrors[0].Field)
+}
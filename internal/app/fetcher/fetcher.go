@@ -0,0 +1,209 @@
+// Package fetcher resolves and retrieves subscription content. It sits
+// between converter.Service and the raw HTTP layer, handling the quirks
+// real subscription providers rely on: short-link redirection, per-host
+// User-Agent sniffing, an optionally base64-encoded body, and traffic
+// accounting reported via the "subscription-userinfo" response header.
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
+	"github.com/subconverter/subconverter-go/internal/infra/config"
+	apphttp "github.com/subconverter/subconverter-go/internal/infra/http"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+)
+
+// UserInfo holds the traffic accounting a provider reports via the
+// "subscription-userinfo" response header (upload/download/total are
+// bytes, Expire is a Unix timestamp).
+type UserInfo struct {
+	Upload   int64 `json:"upload"`
+	Download int64 `json:"download"`
+	Total    int64 `json:"total"`
+	Expire   int64 `json:"expire"`
+}
+
+// Result is the normalized outcome of fetching a subscription URL.
+type Result struct {
+	Body     []byte    `json:"body"`
+	UserInfo *UserInfo `json:"user_info,omitempty"`
+}
+
+// Fetcher resolves a (possibly shortened) subscription URL and returns its
+// normalized content.
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*Result, error)
+}
+
+// DefaultFetcher expands short links via a ShortLinkResolver, fetches the
+// destination honoring per-host User-Agent overrides, transparently
+// base64-decodes a single-blob body, and caches the result by final URL.
+type DefaultFetcher struct {
+	resolver apphttp.ShortLinkResolver
+	client   *apphttp.Client
+	cache    cache.Cache
+	cfg      config.FetcherConfig
+	ttl      time.Duration
+}
+
+// NewDefaultFetcher creates a fetcher that resolves short links with
+// resolver, fetches with client, and caches resolved bodies in store for ttl.
+func NewDefaultFetcher(resolver apphttp.ShortLinkResolver, client *apphttp.Client, store cache.Cache, cfg config.FetcherConfig, ttl time.Duration) *DefaultFetcher {
+	return &DefaultFetcher{
+		resolver: resolver,
+		client:   client,
+		cache:    store,
+		cfg:      cfg,
+		ttl:      ttl,
+	}
+}
+
+// Fetch resolves rawURL, returning the cached result if the final URL was
+// fetched recently, otherwise fetching, normalizing and caching it.
+func (f *DefaultFetcher) Fetch(ctx context.Context, rawURL string) (*Result, error) {
+	resolvedURL, err := f.resolver.Resolve(ctx, rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to resolve short link: %s", rawURL))
+	}
+
+	cacheKey := "fetch:" + resolvedURL
+	if cached, err := f.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
+		var result Result
+		if err := json.Unmarshal(cached, &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	body, headers, err := f.client.GetWithHeaders(f.withUserAgent(ctx, resolvedURL), resolvedURL)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to fetch URL: %s", resolvedURL))
+	}
+
+	result := &Result{
+		Body:     decodeBodyIfBase64(body),
+		UserInfo: parseUserInfo(headers.Get("subscription-userinfo")),
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		f.cache.Set(ctx, cacheKey, data, f.ttl)
+	}
+
+	return result, nil
+}
+
+// withUserAgent applies a configured per-host User-Agent override, unless
+// the caller already asked for a specific one via apphttp.UserAgentKey.
+func (f *DefaultFetcher) withUserAgent(ctx context.Context, rawURL string) context.Context {
+	if ua, ok := ctx.Value(apphttp.UserAgentKey).(string); ok && ua != "" {
+		return ctx
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ctx
+	}
+
+	if ua, ok := f.cfg.UserAgentOverrides[u.Hostname()]; ok && ua != "" {
+		return context.WithValue(ctx, apphttp.UserAgentKey, ua)
+	}
+
+	return ctx
+}
+
+var base64BodyPattern = regexp.MustCompile(`^[A-Za-z0-9+/=_-]+$`)
+
+// decodeBodyIfBase64 returns body decoded when it is, in its entirety, a
+// single base64 blob (no newlines, no proxy-link or YAML syntax); otherwise
+// it returns body unchanged.
+func decodeBodyIfBase64(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || bytes.ContainsAny(trimmed, "\r\n") {
+		return body
+	}
+	if !base64BodyPattern.Match(trimmed) {
+		return body
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(string(trimmed)); err == nil {
+		return decoded
+	}
+	if decoded, err := base64.RawURLEncoding.DecodeString(string(trimmed)); err == nil {
+		return decoded
+	}
+
+	return body
+}
+
+// parseUserInfo parses a "subscription-userinfo" header of the form
+// "upload=1; download=2; total=3; expire=4". It returns nil when header is
+// empty or contains no recognized field.
+func parseUserInfo(header string) *UserInfo {
+	if header == "" {
+		return nil
+	}
+
+	var info UserInfo
+	var found bool
+	for _, field := range strings.Split(header, ";") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		value, err := strconv.ParseInt(strings.TrimSpace(kv[1]), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch strings.TrimSpace(kv[0]) {
+		case "upload":
+			info.Upload = value
+		case "download":
+			info.Download = value
+		case "total":
+			info.Total = value
+		case "expire":
+			info.Expire = value
+		default:
+			continue
+		}
+		found = true
+	}
+
+	if !found {
+		return nil
+	}
+	return &info
+}
+
+// Merge combines per-subscription traffic accounting into a single total:
+// upload/download/total are summed and expire is the soonest non-zero
+// deadline across all sources.
+func Merge(infos ...*UserInfo) *UserInfo {
+	var merged *UserInfo
+	for _, info := range infos {
+		if info == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &UserInfo{}
+		}
+		merged.Upload += info.Upload
+		merged.Download += info.Download
+		merged.Total += info.Total
+		if info.Expire > 0 && (merged.Expire == 0 || info.Expire < merged.Expire) {
+			merged.Expire = info.Expire
+		}
+	}
+	return merged
+}
@@ -53,11 +53,15 @@ func (g *ClashGenerator) Generate(ctx context.Context, proxies []*proxy.Proxy, r
 	
 	// Process rules
 	clashRules := g.buildRules(rulesets, options.Rules)
-	
+
 	// Build configuration
 	config["proxies"] = clashProxies
 	config["proxy-groups"] = clashProxyGroups
 	config["rules"] = clashRules
+
+	if ruleProviders := g.buildRuleProviders(rulesets); len(ruleProviders) > 0 {
+		config["rule-providers"] = ruleProviders
+	}
 	
 	// Add custom options
 	for k, v := range options.CustomOptions {
@@ -110,8 +114,9 @@ func (g *ClashGenerator) buildProxies(proxies []*proxy.Proxy) []map[string]inter
 			proxyMap["uuid"] = p.UUID
 			proxyMap["alterId"] = p.AID
 			proxyMap["cipher"] = p.Method
-			if p.Network != "" {
-				proxyMap["network"] = strings.ToLower(string(p.Network))
+			network := strings.ToLower(string(p.Network))
+			if network != "" {
+				proxyMap["network"] = network
 			}
 			if p.TLS != proxy.TLSNone {
 				proxyMap["tls"] = true
@@ -122,24 +127,72 @@ func (g *ClashGenerator) buildProxies(proxies []*proxy.Proxy) []map[string]inter
 					proxyMap["skip-cert-verify"] = true
 				}
 			}
-			if p.Path != "" || p.Host != "" {
+			switch network {
+			case "grpc":
+				proxyMap["grpc-opts"] = map[string]interface{}{
+					"grpc-service-name": p.ServiceName,
+				}
+			case "ws":
 				proxyMap["ws-opts"] = map[string]interface{}{
 					"path": p.Path,
 					"headers": map[string]string{
 						"Host": p.Host,
 					},
 				}
+			case "h2":
+				proxyMap["h2-opts"] = map[string]interface{}{
+					"path": p.Path,
+					"host": []string{p.Host},
+				}
 			}
-			
+
 		case "vless":
 			proxyMap["uuid"] = p.UUID
-			proxyMap["flow"] = ""
+			proxyMap["flow"] = p.Flow
+			network := strings.ToLower(string(p.Network))
+			if network != "" {
+				proxyMap["network"] = network
+			}
 			if p.TLS != proxy.TLSNone {
 				proxyMap["tls"] = true
 				if p.SNI != "" {
 					proxyMap["servername"] = p.SNI
 				}
 			}
+			if len(p.Alpn) > 0 {
+				proxyMap["alpn"] = p.Alpn
+			}
+			if p.Fingerprint != "" {
+				proxyMap["client-fingerprint"] = p.Fingerprint
+			}
+			if p.PublicKey != "" {
+				realityOpts := map[string]interface{}{
+					"public-key": p.PublicKey,
+					"short-id":   p.ShortID,
+				}
+				if p.SpiderX != "" {
+					realityOpts["spider-x"] = p.SpiderX
+				}
+				proxyMap["reality-opts"] = realityOpts
+			}
+			switch network {
+			case "grpc":
+				proxyMap["grpc-opts"] = map[string]interface{}{
+					"grpc-service-name": p.ServiceName,
+				}
+			case "ws":
+				proxyMap["ws-opts"] = map[string]interface{}{
+					"path": p.Path,
+					"headers": map[string]string{
+						"Host": p.Host,
+					},
+				}
+			case "h2":
+				proxyMap["h2-opts"] = map[string]interface{}{
+					"path": p.Path,
+					"host": []string{p.Host},
+				}
+			}
 			
 		case "trojan":
 			proxyMap["password"] = p.Password
@@ -154,22 +207,52 @@ func (g *ClashGenerator) buildProxies(proxies []*proxy.Proxy) []map[string]inter
 			proxyMap["auth"] = p.Password
 			proxyMap["up"] = fmt.Sprintf("%d Mbps", p.UpMbps)
 			proxyMap["down"] = fmt.Sprintf("%d Mbps", p.DownMbps)
+			if p.Ports != "" {
+				proxyMap["ports"] = p.Ports
+			}
+			if p.Protocol != "" {
+				proxyMap["protocol"] = p.Protocol
+			}
 			if p.SNI != "" {
 				proxyMap["sni"] = p.SNI
 			}
+			if p.Obfs != "" {
+				proxyMap["obfs"] = p.Obfs
+			}
+			if len(p.Alpn) > 0 {
+				proxyMap["alpn"] = p.Alpn
+			}
 			if p.SkipCertVerify {
 				proxyMap["skip-cert-verify"] = true
 			}
-			
+
 		case "hysteria2":
 			proxyMap["password"] = p.Password
+			if p.Ports != "" {
+				proxyMap["ports"] = p.Ports
+			}
+			if p.HopInterval > 0 {
+				proxyMap["hop-interval"] = p.HopInterval
+			}
 			if p.SNI != "" {
 				proxyMap["sni"] = p.SNI
 			}
 			if p.SkipCertVerify {
 				proxyMap["skip-cert-verify"] = true
 			}
-			
+			if p.Obfs != "" {
+				proxyMap["obfs"] = p.Obfs
+				if p.ObfsPassword != "" {
+					proxyMap["obfs-password"] = p.ObfsPassword
+				}
+			}
+			if p.PinSHA256 != "" {
+				proxyMap["fingerprint"] = p.PinSHA256
+			}
+			if p.FastOpen {
+				proxyMap["fast-open"] = true
+			}
+
 		case "snell":
 			proxyMap["psk"] = p.Password
 			proxyMap["version"] = 3
@@ -183,6 +266,16 @@ func (g *ClashGenerator) buildProxies(proxies []*proxy.Proxy) []map[string]inter
 					proxyMap["sni"] = p.SNI
 				}
 			}
+
+		case "ssh":
+			proxyMap["username"] = p.Username
+			proxyMap["password"] = p.Password
+			if p.PrivateKey != "" {
+				proxyMap["private-key"] = p.PrivateKey
+				if p.PrivateKeyPassphrase != "" {
+					proxyMap["private-key-passphrase"] = p.PrivateKeyPassphrase
+				}
+			}
 		}
 		
 		if p.UDP {
@@ -269,31 +362,71 @@ func (g *ClashGenerator) buildProxyGroups(groups []ProxyGroup, proxies []*proxy.
 
 func (g *ClashGenerator) buildRules(rulesets []*ruleset.RuleSet, customRules []string) []string {
 	result := make([]string, 0)
-	
+
 	// Add rules from rulesets
-	for _, ruleset := range rulesets {
-		if !ruleset.Enabled {
+	for _, rs := range rulesets {
+		if !rs.Enabled {
+			continue
+		}
+
+		if rs.Provider != nil {
+			result = append(result, fmt.Sprintf("RULE-SET,%s,%s", rs.Name, rs.Policy))
 			continue
 		}
-		for _, rule := range ruleset.Rules {
+
+		for _, rule := range rs.Rules {
 			clashRule := g.convertRule(rule)
 			if clashRule != "" {
 				result = append(result, clashRule)
 			}
 		}
 	}
-	
+
 	// Add custom rules
 	result = append(result, customRules...)
-	
+
 	// Add default rule
 	if len(result) == 0 {
 		result = append(result, "MATCH,DIRECT")
 	}
-	
+
 	return result
 }
 
+// buildRuleProviders emits a Clash rule-providers block for every ruleset
+// backed by a remote RuleProvider, keyed by ruleset name.
+func (g *ClashGenerator) buildRuleProviders(rulesets []*ruleset.RuleSet) map[string]interface{} {
+	providers := make(map[string]interface{})
+
+	for _, rs := range rulesets {
+		if rs.Provider == nil {
+			continue
+		}
+
+		path := rs.Provider.Path
+		if path == "" {
+			path = fmt.Sprintf("./rule-providers/%s.yaml", rs.Name)
+		}
+
+		entry := map[string]interface{}{
+			"type":     "http",
+			"behavior": string(rs.Provider.Behavior),
+			"url":      rs.Provider.URL,
+			"path":     path,
+		}
+		if rs.Provider.Format != "" {
+			entry["format"] = string(rs.Provider.Format)
+		}
+		if rs.Provider.Interval > 0 {
+			entry["interval"] = rs.Provider.Interval
+		}
+
+		providers[rs.Name] = entry
+	}
+
+	return providers
+}
+
 func (g *ClashGenerator) convertRule(rule ruleset.Rule) string {
 	switch rule.Type {
 	case ruleset.RuleTypeDomain:
@@ -308,6 +441,8 @@ func (g *ClashGenerator) convertRule(rule ruleset.Rule) string {
 		return fmt.Sprintf("IP-CIDR6,%s,%s", rule.Value, rule.Proxy)
 	case ruleset.RuleTypeGeoIP:
 		return fmt.Sprintf("GEOIP,%s,%s", rule.Value, rule.Proxy)
+	case ruleset.RuleTypeGeoSite:
+		return fmt.Sprintf("GEOSITE,%s,%s", rule.Value, rule.Proxy)
 	case ruleset.RuleTypeFinal, ruleset.RuleTypeMatch:
 		return fmt.Sprintf("MATCH,%s", rule.Proxy)
 	default:
@@ -5,7 +5,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/subconverter/subconverter-go/internal/app/parser"
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -123,6 +125,210 @@ func TestClashGenerator_EmptyProxies(t *testing.T) {
 	assert.Contains(t, config, "proxies: []")
 }
 
+func TestClashGenerator_Hysteria2(t *testing.T) {
+	generator := NewClashGenerator(nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		proxy    *proxy.Proxy
+		contains []string
+	}{
+		{
+			name: "full parameter coverage",
+			proxy: &proxy.Proxy{
+				Type:           proxy.TypeHysteria2,
+				Server:         "example.com",
+				Port:           443,
+				Password:       "secret",
+				SNI:            "example.com",
+				Obfs:           "salamander",
+				ObfsPassword:   "obfspass",
+				PinSHA256:      "AA:BB",
+				SkipCertVerify: true,
+				Name:           "Test-Hysteria2",
+			},
+			contains: []string{
+				"type: hysteria2",
+				"password: secret",
+				"sni: example.com",
+				"obfs: salamander",
+				"obfs-password: obfspass",
+				"fingerprint: AA:BB",
+				"skip-cert-verify: true",
+			},
+		},
+		{
+			name: "port hopping",
+			proxy: &proxy.Proxy{
+				Type:        proxy.TypeHysteria2,
+				Server:      "example.com",
+				Port:        20000,
+				Ports:       "20000-40000,50000",
+				Password:    "secret",
+				HopInterval: 30,
+				Name:        "Test-Hysteria2-Hop",
+			},
+			contains: []string{
+				`ports: 20000-40000,50000`,
+				"hop-interval: 30",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := generator.Generate(ctx, []*proxy.Proxy{tt.proxy}, nil, GenerateOptions{})
+			require.NoError(t, err)
+
+			for _, substr := range tt.contains {
+				assert.Contains(t, config, substr)
+			}
+		})
+	}
+}
+
+func TestClashGenerator_Hysteria(t *testing.T) {
+	generator := NewClashGenerator(nil)
+	ctx := context.Background()
+
+	p := &proxy.Proxy{
+		Type:           proxy.Type("hysteria"),
+		Server:         "example.com",
+		Port:           443,
+		Password:       "secret",
+		Protocol:       "udp",
+		SNI:            "example.com",
+		Obfs:           "xplus",
+		Alpn:           []string{"h3"},
+		UpMbps:         10,
+		DownMbps:       50,
+		Ports:          "20000-30000",
+		SkipCertVerify: true,
+		Name:           "Test-Hysteria",
+	}
+
+	config, err := generator.Generate(ctx, []*proxy.Proxy{p}, nil, GenerateOptions{})
+	require.NoError(t, err)
+
+	for _, substr := range []string{
+		"type: hysteria",
+		"auth: secret",
+		"protocol: udp",
+		"sni: example.com",
+		"obfs: xplus",
+		"ports: 20000-30000",
+		"skip-cert-verify: true",
+	} {
+		assert.Contains(t, config, substr)
+	}
+}
+
+func TestClashGenerator_Hysteria2_FastOpen(t *testing.T) {
+	generator := NewClashGenerator(nil)
+	ctx := context.Background()
+
+	p := &proxy.Proxy{
+		Type:     proxy.TypeHysteria2,
+		Server:   "example.com",
+		Port:     443,
+		Password: "secret",
+		FastOpen: true,
+		Name:     "Test-Hysteria2-FastOpen",
+	}
+
+	config, err := generator.Generate(ctx, []*proxy.Proxy{p}, nil, GenerateOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, config, "fast-open: true")
+}
+
+func TestClashGenerator_RuleProvider(t *testing.T) {
+	generator := NewClashGenerator(nil)
+	ctx := context.Background()
+
+	rulesets := []*ruleset.RuleSet{
+		{
+			Name:    "ad-block",
+			Enabled: true,
+			Policy:  "REJECT",
+			Provider: &ruleset.RuleProvider{
+				URL:      "https://example.com/ad-block.yaml",
+				Behavior: ruleset.BehaviorDomain,
+				Interval: 86400,
+			},
+		},
+	}
+
+	config, err := generator.Generate(ctx, nil, rulesets, GenerateOptions{})
+	require.NoError(t, err)
+
+	assert.Contains(t, config, "rule-providers:")
+	assert.Contains(t, config, "ad-block:")
+	assert.Contains(t, config, "type: http")
+	assert.Contains(t, config, "behavior: domain")
+	assert.Contains(t, config, "url: https://example.com/ad-block.yaml")
+	assert.Contains(t, config, "interval: 86400")
+	assert.Contains(t, config, "- RULE-SET,ad-block,REJECT")
+}
+
+func TestClashGenerator_VLESS_RoundTrip(t *testing.T) {
+	generator := NewClashGenerator(nil)
+	ctx := context.Background()
+	vlessParser := parser.NewVLESSParser()
+
+	tests := []struct {
+		name     string
+		input    string
+		contains []string
+	}{
+		{
+			name:  "reality",
+			input: "vless://uuid-1@example.com:443?security=reality&flow=xtls-rprx-vision&fp=chrome&pbk=pubkey&sid=shortid&spx=%2F&type=tcp#Reality-Node",
+			contains: []string{
+				"type: vless",
+				"flow: xtls-rprx-vision",
+				"client-fingerprint: chrome",
+				"public-key: pubkey",
+				"short-id: shortid",
+				"spider-x: /",
+			},
+		},
+		{
+			name:  "grpc",
+			input: "vless://uuid-2@example.com:443?security=tls&type=grpc&serviceName=grpc-service&mode=gun#GRPC-Node",
+			contains: []string{
+				"network: grpc",
+				"grpc-service-name: grpc-service",
+				"tls: true",
+			},
+		},
+		{
+			name:  "ws",
+			input: "vless://uuid-3@example.com:443?security=tls&type=ws&path=%2Fws&host=example.com#WS-Node",
+			contains: []string{
+				"network: ws",
+				"path: /ws",
+				"Host: example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxies, err := vlessParser.Parse(ctx, tt.input)
+			require.NoError(t, err)
+			require.Len(t, proxies, 1)
+
+			config, err := generator.Generate(ctx, proxies, nil, GenerateOptions{})
+			require.NoError(t, err)
+
+			for _, substr := range tt.contains {
+				assert.Contains(t, config, substr)
+			}
+		})
+	}
+}
+
 func BenchmarkClashGenerator_Generate(b *testing.B) {
 	generator := NewClashGenerator(nil)
 	ctx := context.Background()
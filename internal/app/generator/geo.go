@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/subconverter/subconverter-go/internal/app/geodata"
+)
+
+// expandGeoRule inlines a single "GEOIP,<code>,<policy>" or
+// "GEOSITE,<tag>,<policy>" custom rule line into concrete IP-CIDR/DOMAIN/
+// DOMAIN-SUFFIX/DOMAIN-KEYWORD lines via compiler, for targets that can't
+// consult a geo database themselves. Any other rule line, or a GEOIP/
+// GEOSITE line compiler has no data for, passes through unchanged.
+func expandGeoRule(ctx context.Context, compiler geodata.RulesetCompiler, line string) []string {
+	if compiler == nil {
+		return []string{line}
+	}
+
+	parts := strings.SplitN(line, ",", 3)
+	if len(parts) < 3 {
+		return []string{line}
+	}
+	ruleType, value, policy := parts[0], parts[1], parts[2]
+
+	switch strings.ToUpper(ruleType) {
+	case "GEOIP":
+		cidrs, err := compiler.ExpandGeoIP(ctx, value)
+		if err != nil {
+			return []string{line}
+		}
+		expanded := make([]string, len(cidrs))
+		for i, cidr := range cidrs {
+			expanded[i] = fmt.Sprintf("IP-CIDR,%s,%s", cidr, policy)
+		}
+		return expanded
+	case "GEOSITE":
+		entries, err := compiler.ExpandGeoSite(ctx, value)
+		if err != nil {
+			return []string{line}
+		}
+		expanded := make([]string, 0, len(entries.Domains)+len(entries.Suffixes)+len(entries.Keywords))
+		for _, domain := range entries.Domains {
+			expanded = append(expanded, fmt.Sprintf("DOMAIN,%s,%s", domain, policy))
+		}
+		for _, suffix := range entries.Suffixes {
+			expanded = append(expanded, fmt.Sprintf("DOMAIN-SUFFIX,%s,%s", suffix, policy))
+		}
+		for _, keyword := range entries.Keywords {
+			expanded = append(expanded, fmt.Sprintf("DOMAIN-KEYWORD,%s,%s", keyword, policy))
+		}
+		if len(expanded) == 0 {
+			return []string{line}
+		}
+		return expanded
+	default:
+		return []string{line}
+	}
+}
@@ -3,9 +3,11 @@ package generator
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
 	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+	"github.com/subconverter/subconverter-go/internal/pkg/middleware"
 )
 
 // Generator defines the interface for generating configuration files
@@ -46,16 +48,19 @@ type ProxyGroup struct {
 	Strategy  string   `json:"strategy,omitempty"`
 }
 
-// RenameRule defines rules for renaming proxies
+// RenameRule rewrites proxy names matching a regular expression. Replace
+// may reference capture groups from Match (e.g. "$1").
 type RenameRule struct {
 	Match   string `json:"match"`
 	Replace string `json:"replace"`
 }
 
-// EmojiRule defines rules for adding emojis to proxy names
+// EmojiRule tags proxy names containing Match with Emoji. Position is
+// "prefix" (default) or "suffix".
 type EmojiRule struct {
-	Match string `json:"match"`
-	Emoji string `json:"emoji"`
+	Match    string `json:"match"`
+	Emoji    string `json:"emoji"`
+	Position string `json:"position,omitempty"`
 }
 
 // Manager manages multiple generators
@@ -96,6 +101,10 @@ func (m *Manager) Generate(ctx context.Context, format string, proxies []*proxy.
 	if !exists {
 		return "", fmt.Errorf("unsupported format: %s", format)
 	}
-	
-	return generator.Generate(ctx, proxies, rulesets, options)
+
+	start := time.Now()
+	config, err := generator.Generate(ctx, proxies, rulesets, options)
+	middleware.GeneratorMetrics(format, time.Since(start), err == nil)
+
+	return config, err
 }
\ No newline at end of file
@@ -0,0 +1,352 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+)
+
+// SortBy selects how NameProcessor orders proxies once renaming is done.
+type SortBy string
+
+const (
+	SortByNone    SortBy = "none"
+	SortByName    SortBy = "name"
+	SortByLatency SortBy = "latency"
+	SortByRegion  SortBy = "region"
+)
+
+// DeduplicateBy selects which proxy fields NameProcessor compares to drop
+// duplicates.
+type DeduplicateBy string
+
+const (
+	DeduplicateByServerPort DeduplicateBy = "server+port"
+	DeduplicateByName       DeduplicateBy = "name"
+	DeduplicateByFull       DeduplicateBy = "full"
+)
+
+// NameProcessor renames, emoji-tags, deduplicates, and sorts proxies before
+// they're handed to a Generator. Service.Convert runs it once on the merged
+// proxy list so every output format sees the same names.
+type NameProcessor struct {
+	RenameRules   []RenameRule
+	EmojiRules    []EmojiRule
+	AutoEmoji     bool
+	SortBy        SortBy
+	DeduplicateBy DeduplicateBy
+}
+
+// Process renames, tags, deduplicates, and sorts proxies in that order,
+// returning a new slice. The input slice is left untouched, but its *Proxy
+// elements are mutated in place (names are rewritten on the shared value).
+func (p *NameProcessor) Process(proxies []*proxy.Proxy) []*proxy.Proxy {
+	result := make([]*proxy.Proxy, len(proxies))
+	copy(result, proxies)
+
+	p.rename(result)
+	p.tagEmoji(result)
+	result = p.deduplicate(result)
+	p.sort(result)
+
+	return result
+}
+
+// rename applies every RenameRule in order, treating Match as a Go regexp
+// so rules can reference capture groups in Replace (e.g. "$1").
+func (p *NameProcessor) rename(proxies []*proxy.Proxy) {
+	for _, rule := range p.RenameRules {
+		re, err := regexp.Compile(rule.Match)
+		if err != nil {
+			continue
+		}
+		for _, pr := range proxies {
+			pr.Name = re.ReplaceAllString(pr.Name, rule.Replace)
+		}
+	}
+}
+
+func (p *NameProcessor) tagEmoji(proxies []*proxy.Proxy) {
+	for _, pr := range proxies {
+		for _, rule := range p.EmojiRules {
+			if strings.Contains(pr.Name, rule.Match) {
+				pr.Name = applyEmoji(pr.Name, rule.Emoji, rule.Position)
+			}
+		}
+		if p.AutoEmoji && !hasFlagEmoji(pr.Name) {
+			if flag, ok := detectRegionFlag(pr.Name); ok {
+				pr.Name = flag + " " + pr.Name
+			}
+		}
+	}
+}
+
+func applyEmoji(name, emoji, position string) string {
+	if position == "suffix" {
+		return name + " " + emoji
+	}
+	return emoji + " " + name
+}
+
+func (p *NameProcessor) deduplicate(proxies []*proxy.Proxy) []*proxy.Proxy {
+	by := p.DeduplicateBy
+	if by == "" {
+		by = DeduplicateByServerPort
+	}
+
+	seen := make(map[string]bool, len(proxies))
+	result := make([]*proxy.Proxy, 0, len(proxies))
+	for _, pr := range proxies {
+		key := dedupeKey(pr, by)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, pr)
+	}
+	return result
+}
+
+func dedupeKey(pr *proxy.Proxy, by DeduplicateBy) string {
+	switch by {
+	case DeduplicateByName:
+		return pr.Name
+	case DeduplicateByFull:
+		data, _ := json.Marshal(pr)
+		return string(data)
+	default:
+		return fmt.Sprintf("%s:%d:%s", pr.Server, pr.Port, pr.Type)
+	}
+}
+
+func (p *NameProcessor) sort(proxies []*proxy.Proxy) {
+	switch p.SortBy {
+	case SortByName:
+		sort.SliceStable(proxies, func(i, j int) bool { return proxies[i].Name < proxies[j].Name })
+	case SortByLatency:
+		sort.SliceStable(proxies, func(i, j int) bool { return proxies[i].Latency < proxies[j].Latency })
+	case SortByRegion:
+		sort.SliceStable(proxies, func(i, j int) bool { return regionKey(proxies[i].Name) < regionKey(proxies[j].Name) })
+	}
+}
+
+func regionKey(name string) string {
+	if code, ok := detectRegionCode(name); ok {
+		return code
+	}
+	return "~" + name
+}
+
+// hasFlagEmoji reports whether name already carries a Unicode regional
+// indicator flag, so AutoEmoji doesn't double-tag names a provider already
+// flagged.
+func hasFlagEmoji(name string) bool {
+	for _, r := range name {
+		if r >= 0x1F1E6 && r <= 0x1F1FF {
+			return true
+		}
+	}
+	return false
+}
+
+func detectRegionFlag(name string) (string, bool) {
+	code, ok := detectRegionCode(name)
+	if !ok {
+		return "", false
+	}
+	return flagEmoji(code), true
+}
+
+func detectRegionCode(name string) (string, bool) {
+	for _, m := range regionMatchers {
+		if m.match(name) {
+			return m.code, true
+		}
+	}
+	return "", false
+}
+
+// flagEmoji builds the regional-indicator flag emoji for a 2-letter
+// ISO-3166 alpha-2 code (e.g. "JP" -> "🇯🇵").
+func flagEmoji(code string) string {
+	code = strings.ToUpper(code)
+	if len(code) != 2 {
+		return ""
+	}
+	letters := []rune(code)
+	return string([]rune{
+		0x1F1E6 + (letters[0] - 'A'),
+		0x1F1E6 + (letters[1] - 'A'),
+	})
+}
+
+type regionMatcher struct {
+	code  string
+	match func(name string) bool
+}
+
+var regionMatchers = buildRegionMatchers()
+
+// buildRegionMatchers precompiles a matcher per alias: short ASCII codes
+// (hk, jp, us, ...) require a non-letter boundary so they don't fire inside
+// unrelated words, while longer words and CJK aliases match as a plain
+// substring.
+func buildRegionMatchers() []regionMatcher {
+	matchers := make([]regionMatcher, 0, len(regionAliases))
+	for alias, code := range regionAliases {
+		alias, code := alias, code
+		if isShortASCII(alias) {
+			re := regexp.MustCompile(`(?i)(^|[^a-zA-Z])` + regexp.QuoteMeta(alias) + `([^a-zA-Z]|$)`)
+			matchers = append(matchers, regionMatcher{code: code, match: re.MatchString})
+			continue
+		}
+		lower := strings.ToLower(alias)
+		matchers = append(matchers, regionMatcher{code: code, match: func(name string) bool {
+			return strings.Contains(strings.ToLower(name), lower)
+		}})
+	}
+	return matchers
+}
+
+func isShortASCII(s string) bool {
+	if len(s) > 3 {
+		return false
+	}
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// regionAliases maps a lowercase token seen in real subscription names to
+// the ISO-3166 alpha-2 region code it identifies. Covers English, Chinese,
+// and Japanese spellings for the regions that show up most often.
+var regionAliases = map[string]string{
+	"hk":         "HK",
+	"hongkong":   "HK",
+	"hong kong":  "HK",
+	"香港":         "HK",
+	"港":          "HK",
+	"ホンコン":      "HK",
+
+	"tw":       "TW",
+	"taiwan":   "TW",
+	"台湾":       "TW",
+	"台灣":       "TW",
+	"タイワン":     "TW",
+
+	"jp":    "JP",
+	"japan": "JP",
+	"日本":    "JP",
+	"にほん":   "JP",
+
+	"kr":          "KR",
+	"korea":       "KR",
+	"south korea": "KR",
+	"韩国":          "KR",
+	"韓國":          "KR",
+	"カンコク":       "KR",
+
+	"sg":          "SG",
+	"singapore":   "SG",
+	"新加坡":        "SG",
+	"狮城":          "SG",
+	"シンガポール":    "SG",
+
+	"us":            "US",
+	"usa":           "US",
+	"united states": "US",
+	"美国":            "US",
+	"美國":            "US",
+	"アメリカ":         "US",
+
+	"uk":             "GB",
+	"gb":             "GB",
+	"united kingdom": "GB",
+	"英国":             "GB",
+	"英國":             "GB",
+	"イギリス":          "GB",
+
+	"de":      "DE",
+	"germany": "DE",
+	"德国":      "DE",
+	"德國":      "DE",
+	"ドイツ":     "DE",
+
+	"fr":     "FR",
+	"france": "FR",
+	"法国":     "FR",
+	"法國":     "FR",
+	"フランス":   "FR",
+
+	"ca":     "CA",
+	"canada": "CA",
+	"加拿大":    "CA",
+	"カナダ":    "CA",
+
+	"au":        "AU",
+	"australia": "AU",
+	"澳大利亚":     "AU",
+	"澳洲":       "AU",
+	"オーストラリア":  "AU",
+
+	"ru":     "RU",
+	"russia": "RU",
+	"俄罗斯":    "RU",
+	"俄羅斯":    "RU",
+	"ロシア":    "RU",
+
+	"in":    "IN",
+	"india": "IN",
+	"印度":    "IN",
+	"インド":   "IN",
+
+	"cn":      "CN",
+	"china":   "CN",
+	"中国":      "CN",
+	"中國":      "CN",
+	"大陆":      "CN",
+	"チャイナ":    "CN",
+
+	"my":       "MY",
+	"malaysia": "MY",
+	"马来西亚":    "MY",
+	"馬來西亞":    "MY",
+
+	"th":       "TH",
+	"thailand": "TH",
+	"泰国":       "TH",
+	"泰國":       "TH",
+
+	"vn":      "VN",
+	"vietnam": "VN",
+	"越南":      "VN",
+
+	"ph":          "PH",
+	"philippines": "PH",
+	"菲律宾":        "PH",
+
+	"id":        "ID",
+	"indonesia": "ID",
+	"印尼":        "ID",
+
+	"mo":    "MO",
+	"macau": "MO",
+	"澳门":    "MO",
+	"澳門":    "MO",
+
+	"tr":     "TR",
+	"turkey": "TR",
+	"土耳其":   "TR",
+
+	"br":     "BR",
+	"brazil": "BR",
+	"巴西":    "BR",
+}
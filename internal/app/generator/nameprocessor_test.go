@@ -0,0 +1,171 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func newNamedProxy(name string) *proxy.Proxy {
+	return &proxy.Proxy{Name: name, Server: name, Port: 443, Type: proxy.Shadowsocks}
+}
+
+func TestNameProcessor_Rename(t *testing.T) {
+	p := &NameProcessor{
+		RenameRules: []RenameRule{
+			{Match: `^\[Provider\] (.+)$`, Replace: "$1"},
+		},
+	}
+
+	result := p.Process([]*proxy.Proxy{newNamedProxy("[Provider] Tokyo-01")})
+	assert.Equal(t, "Tokyo-01", result[0].Name)
+}
+
+func TestNameProcessor_EmojiRule(t *testing.T) {
+	p := &NameProcessor{
+		EmojiRules: []EmojiRule{
+			{Match: "Tokyo", Emoji: "🗼"},
+			{Match: "Node", Emoji: "⭐", Position: "suffix"},
+		},
+	}
+
+	result := p.Process([]*proxy.Proxy{newNamedProxy("Tokyo Node")})
+	assert.Equal(t, "🗼 Tokyo Node ⭐", result[0].Name)
+}
+
+func TestNameProcessor_Deduplicate(t *testing.T) {
+	tests := []struct {
+		name string
+		by   DeduplicateBy
+		want int
+	}{
+		{"default server+port", "", 1},
+		{"by name", DeduplicateByName, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxies := []*proxy.Proxy{
+				{Name: "A", Server: "1.1.1.1", Port: 443, Type: proxy.Shadowsocks},
+				{Name: "A", Server: "1.1.1.1", Port: 443, Type: proxy.Shadowsocks},
+			}
+			p := &NameProcessor{DeduplicateBy: tt.by}
+			result := p.Process(proxies)
+			assert.Len(t, result, tt.want)
+		})
+	}
+}
+
+func TestNameProcessor_SortByName(t *testing.T) {
+	p := &NameProcessor{SortBy: SortByName}
+	result := p.Process([]*proxy.Proxy{
+		newNamedProxy("Charlie"),
+		{Name: "Alpha", Server: "2", Port: 443, Type: proxy.Shadowsocks},
+		{Name: "Bravo", Server: "3", Port: 443, Type: proxy.Shadowsocks},
+	})
+	assert.Equal(t, []string{"Alpha", "Bravo", "Charlie"}, []string{result[0].Name, result[1].Name, result[2].Name})
+}
+
+func TestNameProcessor_SortByLatency(t *testing.T) {
+	p := &NameProcessor{SortBy: SortByLatency}
+	result := p.Process([]*proxy.Proxy{
+		{Name: "Slow", Server: "1", Port: 443, Type: proxy.Shadowsocks, Latency: 300},
+		{Name: "Fast", Server: "2", Port: 443, Type: proxy.Shadowsocks, Latency: 50},
+	})
+	assert.Equal(t, "Fast", result[0].Name)
+	assert.Equal(t, "Slow", result[1].Name)
+}
+
+func TestNameProcessor_AutoEmoji(t *testing.T) {
+	p := &NameProcessor{AutoEmoji: true}
+	result := p.Process([]*proxy.Proxy{newNamedProxy("HK Node 01")})
+	assert.Contains(t, result[0].Name, "🇭🇰")
+}
+
+func TestNameProcessor_AutoEmojiSkipsExistingFlag(t *testing.T) {
+	p := &NameProcessor{AutoEmoji: true}
+	result := p.Process([]*proxy.Proxy{newNamedProxy("🇯🇵 HK Node 01")})
+	assert.Equal(t, "🇯🇵 HK Node 01", result[0].Name)
+}
+
+func TestDetectRegionFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"HK", "🇭🇰"},
+		{"Hong Kong 01", "🇭🇰"},
+		{"香港节点", "🇭🇰"},
+		{"港01", "🇭🇰"},
+		{"ホンコン01", "🇭🇰"},
+		{"TW", "🇹🇼"},
+		{"Taiwan-02", "🇹🇼"},
+		{"台湾01", "🇹🇼"},
+		{"台灣01", "🇹🇼"},
+		{"タイワン01", "🇹🇼"},
+		{"JP", "🇯🇵"},
+		{"Japan Tokyo", "🇯🇵"},
+		{"日本01", "🇯🇵"},
+		{"にほん01", "🇯🇵"},
+		{"KR", "🇰🇷"},
+		{"South Korea", "🇰🇷"},
+		{"韩国01", "🇰🇷"},
+		{"韓國01", "🇰🇷"},
+		{"カンコク01", "🇰🇷"},
+		{"SG", "🇸🇬"},
+		{"Singapore-1", "🇸🇬"},
+		{"新加坡01", "🇸🇬"},
+		{"狮城01", "🇸🇬"},
+		{"シンガポール01", "🇸🇬"},
+		{"US", "🇺🇸"},
+		{"United States", "🇺🇸"},
+		{"美国01", "🇺🇸"},
+		{"美國01", "🇺🇸"},
+		{"アメリカ01", "🇺🇸"},
+		{"UK", "🇬🇧"},
+		{"United Kingdom", "🇬🇧"},
+		{"英国01", "🇬🇧"},
+		{"Germany-1", "🇩🇪"},
+		{"德国01", "🇩🇪"},
+		{"France-1", "🇫🇷"},
+		{"法国01", "🇫🇷"},
+		{"Canada-1", "🇨🇦"},
+		{"加拿大01", "🇨🇦"},
+		{"Australia-1", "🇦🇺"},
+		{"澳大利亚01", "🇦🇺"},
+		{"Russia-1", "🇷🇺"},
+		{"俄罗斯01", "🇷🇺"},
+		{"India-1", "🇮🇳"},
+		{"印度01", "🇮🇳"},
+		{"China-1", "🇨🇳"},
+		{"中国01", "🇨🇳"},
+		{"Malaysia-1", "🇲🇾"},
+		{"Thailand-1", "🇹🇭"},
+		{"Vietnam-1", "🇻🇳"},
+		{"Philippines-1", "🇵🇭"},
+		{"Indonesia-1", "🇮🇩"},
+		{"Macau-1", "🇲🇴"},
+		{"Turkey-1", "🇹🇷"},
+		{"Brazil-1", "🇧🇷"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := detectRegionFlag(tt.name)
+			assert.True(t, ok, "expected %q to match a region", tt.name)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDetectRegionFlag_NoMatch(t *testing.T) {
+	_, ok := detectRegionFlag("Unnamed Node 01")
+	assert.False(t, ok)
+}
+
+func TestDetectRegionFlag_ShortCodeRequiresBoundary(t *testing.T) {
+	// "us" must not match inside an unrelated word.
+	_, ok := detectRegionFlag("house-party")
+	assert.False(t, ok)
+}
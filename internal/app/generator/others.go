@@ -2,9 +2,11 @@ package generator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/subconverter/subconverter-go/internal/app/geodata"
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
 	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
 )
@@ -61,15 +63,104 @@ func (g *LoonGenerator) buildProxyLine(proxy *proxy.Proxy) string {
 	}
 }
 
-// V2RayGenerator generates V2Ray configuration
-type V2RayGenerator struct{}
+// V2RayGenerator generates V2Ray configuration. Unlike Clash/Surge/
+// sing-box, V2Ray has no bundled GeoIP/GeoSite database of its own to
+// reference by tag, so an optional compiler inlines GEOIP/GEOSITE custom
+// rules into concrete ip/domain entries at generation time; with no
+// compiler configured, those rules fall back to V2Ray's native
+// "geoip:"/"geosite:" routing tag syntax.
+type V2RayGenerator struct {
+	compiler geodata.RulesetCompiler
+}
 
-func NewV2RayGenerator() *V2RayGenerator { return &V2RayGenerator{} }
-func (g *V2RayGenerator) Format() string { return "v2ray" }
+func NewV2RayGenerator(compiler geodata.RulesetCompiler) *V2RayGenerator {
+	return &V2RayGenerator{compiler: compiler}
+}
+func (g *V2RayGenerator) Format() string      { return "v2ray" }
 func (g *V2RayGenerator) ContentType() string { return "application/json" }
 func (g *V2RayGenerator) Generate(ctx context.Context, proxies []*proxy.Proxy, rulesets []*ruleset.RuleSet, options GenerateOptions) (string, error) {
-	result := fmt.Sprintf(`{"outbounds": [{"protocol": "freedom", "tag": "direct"}]}`)
-	return result, nil
+	result := map[string]interface{}{
+		"outbounds": []map[string]interface{}{
+			{"protocol": "freedom", "tag": "direct"},
+		},
+	}
+
+	if rules := g.buildRoutingRules(ctx, options.Rules); len(rules) > 0 {
+		result["routing"] = map[string]interface{}{
+			"domainStrategy": "AsIs",
+			"rules":          rules,
+		}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal v2ray config: %w", err)
+	}
+	return string(data), nil
+}
+
+// routingBucket accumulates the ip/domain values routed to one outbound
+// tag, before they're flattened into a single V2Ray routing rule object.
+type routingBucket struct {
+	ip     []string
+	domain []string
+}
+
+// buildRoutingRules expands each custom rule line (inlining any GEOIP/
+// GEOSITE entry via g.compiler) and groups the results into V2Ray routing
+// rule objects keyed by outbound tag, in first-seen order.
+func (g *V2RayGenerator) buildRoutingRules(ctx context.Context, lines []string) []map[string]interface{} {
+	buckets := make(map[string]*routingBucket)
+	order := make([]string, 0)
+
+	for _, line := range lines {
+		for _, expanded := range expandGeoRule(ctx, g.compiler, line) {
+			parts := strings.SplitN(expanded, ",", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			ruleType, value, policy := strings.ToUpper(parts[0]), parts[1], parts[2]
+
+			bucket, ok := buckets[policy]
+			if !ok {
+				bucket = &routingBucket{}
+				buckets[policy] = bucket
+				order = append(order, policy)
+			}
+
+			switch ruleType {
+			case "IP-CIDR", "IP-CIDR6":
+				bucket.ip = append(bucket.ip, value)
+			case "GEOIP":
+				bucket.ip = append(bucket.ip, "geoip:"+strings.ToLower(value))
+			case "DOMAIN", "DOMAIN-SUFFIX", "DOMAIN-KEYWORD":
+				bucket.domain = append(bucket.domain, value)
+			case "GEOSITE":
+				bucket.domain = append(bucket.domain, "geosite:"+strings.ToLower(value))
+			}
+		}
+	}
+
+	rules := make([]map[string]interface{}, 0, len(order))
+	for _, policy := range order {
+		bucket := buckets[policy]
+		if len(bucket.ip) == 0 && len(bucket.domain) == 0 {
+			continue
+		}
+
+		rule := map[string]interface{}{
+			"type":        "field",
+			"outboundTag": policy,
+		}
+		if len(bucket.ip) > 0 {
+			rule["ip"] = bucket.ip
+		}
+		if len(bucket.domain) > 0 {
+			rule["domain"] = bucket.domain
+		}
+		rules = append(rules, rule)
+	}
+	return rules
 }
 
 // SurfboardGenerator generates Surfboard configuration
@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/subconverter/subconverter-go/internal/app/geodata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV2RayGenerator_GeoRulesFallBackToNativeTagsWithoutCompiler(t *testing.T) {
+	generator := NewV2RayGenerator(nil)
+	ctx := context.Background()
+
+	config, err := generator.Generate(ctx, nil, nil, GenerateOptions{
+		Rules: []string{"GEOIP,CN,direct", "GEOSITE,netflix,proxy"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, config, `"geoip:cn"`)
+	assert.Contains(t, config, `"geosite:netflix"`)
+	assert.Contains(t, config, `"outboundTag":"direct"`)
+	assert.Contains(t, config, `"outboundTag":"proxy"`)
+}
+
+func TestV2RayGenerator_GeoRulesInlinedWithCompiler(t *testing.T) {
+	compiler := geodata.NewCompiler()
+	require.NoError(t, compiler.LoadCIDRs("CN", []string{"1.0.1.0/24"}))
+
+	generator := NewV2RayGenerator(compiler)
+	ctx := context.Background()
+
+	config, err := generator.Generate(ctx, nil, nil, GenerateOptions{
+		Rules: []string{"GEOIP,CN,direct"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, config, `"1.0.1.0/24"`)
+	assert.NotContains(t, config, `"geoip:cn"`)
+}
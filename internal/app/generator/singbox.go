@@ -0,0 +1,316 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+)
+
+// SingBoxGenerator generates a sing-box (1.8+) JSON configuration: one
+// outbound per proxy, a selector/urltest outbound per proxy group, and a
+// route block translated from the ruleset.
+type SingBoxGenerator struct{}
+
+func NewSingBoxGenerator() *SingBoxGenerator { return &SingBoxGenerator{} }
+
+func (g *SingBoxGenerator) Format() string { return "sing-box" }
+
+func (g *SingBoxGenerator) ContentType() string { return "application/json" }
+
+func (g *SingBoxGenerator) Generate(ctx context.Context, proxies []*proxy.Proxy, rulesets []*ruleset.RuleSet, options GenerateOptions) (string, error) {
+	outbounds := make([]map[string]interface{}, 0, len(proxies)+len(options.ProxyGroups)+2)
+
+	for _, p := range proxies {
+		if outbound := g.buildOutbound(p); outbound != nil {
+			outbounds = append(outbounds, outbound)
+		}
+	}
+
+	for _, group := range options.ProxyGroups {
+		outbounds = append(outbounds, g.buildGroupOutbound(group))
+	}
+
+	outbounds = append(outbounds,
+		map[string]interface{}{"type": "direct", "tag": "direct"},
+		map[string]interface{}{"type": "block", "tag": "block"},
+	)
+
+	route := map[string]interface{}{
+		"rules": g.buildRouteRules(rulesets),
+		"final": "direct",
+	}
+	if ruleSets := g.buildRuleSets(rulesets); len(ruleSets) > 0 {
+		route["rule_set"] = ruleSets
+	}
+
+	config := map[string]interface{}{
+		"outbounds": outbounds,
+		"route":     route,
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (g *SingBoxGenerator) buildOutbound(p *proxy.Proxy) map[string]interface{} {
+	outbound := map[string]interface{}{
+		"tag":         p.Name,
+		"server":      p.Server,
+		"server_port": p.Port,
+	}
+
+	switch p.Type {
+	case proxy.TypeShadowsocks:
+		outbound["type"] = "shadowsocks"
+		outbound["method"] = p.Method
+		outbound["password"] = p.Password
+
+	case proxy.TypeVMess:
+		outbound["type"] = "vmess"
+		outbound["uuid"] = p.UUID
+		outbound["alter_id"] = p.AID
+		outbound["security"] = p.Method
+		if p.TLS != proxy.TLSNone {
+			outbound["tls"] = g.buildTLS(p)
+		}
+		if transport := g.buildTransport(p); transport != nil {
+			outbound["transport"] = transport
+		}
+
+	case proxy.TypeVLESS:
+		outbound["type"] = "vless"
+		outbound["uuid"] = p.UUID
+		if p.Flow != "" {
+			outbound["flow"] = p.Flow
+		}
+		if p.TLS != proxy.TLSNone {
+			outbound["tls"] = g.buildTLS(p)
+		}
+		if transport := g.buildTransport(p); transport != nil {
+			outbound["transport"] = transport
+		}
+
+	case proxy.TypeTrojan:
+		outbound["type"] = "trojan"
+		outbound["password"] = p.Password
+		outbound["tls"] = g.buildTLS(p)
+		if transport := g.buildTransport(p); transport != nil {
+			outbound["transport"] = transport
+		}
+
+	case proxy.TypeHysteria:
+		outbound["type"] = "hysteria"
+		outbound["auth_str"] = p.Password
+		outbound["up_mbps"] = p.UpMbps
+		outbound["down_mbps"] = p.DownMbps
+		if p.Obfs != "" {
+			outbound["obfs"] = p.Obfs
+		}
+		outbound["tls"] = g.buildTLS(p)
+
+	case proxy.TypeHysteria2:
+		outbound["type"] = "hysteria2"
+		outbound["password"] = p.Password
+		if p.Obfs != "" {
+			outbound["obfs"] = map[string]interface{}{
+				"type":     p.Obfs,
+				"password": p.ObfsPassword,
+			}
+		}
+		outbound["tls"] = g.buildTLS(p)
+
+	case proxy.TypeSocks5:
+		outbound["type"] = "socks"
+		outbound["version"] = "5"
+		if p.Username != "" {
+			outbound["username"] = p.Username
+			outbound["password"] = p.Password
+		}
+
+	case proxy.TypeHTTP, proxy.TypeHTTPS:
+		outbound["type"] = "http"
+		if p.Username != "" {
+			outbound["username"] = p.Username
+			outbound["password"] = p.Password
+		}
+		if p.Type == proxy.TypeHTTPS {
+			outbound["tls"] = g.buildTLS(p)
+		}
+
+	case proxy.Type("tuic"):
+		outbound["type"] = "tuic"
+		outbound["uuid"] = p.UUID
+		outbound["password"] = p.Password
+		outbound["tls"] = g.buildTLS(p)
+
+	case proxy.Type("wireguard"):
+		outbound["type"] = "wireguard"
+		outbound["private_key"] = p.PrivateKey
+		outbound["peer_public_key"] = p.PublicKey
+
+	default:
+		return nil
+	}
+
+	return outbound
+}
+
+func (g *SingBoxGenerator) buildTLS(p *proxy.Proxy) map[string]interface{} {
+	tls := map[string]interface{}{
+		"enabled": true,
+	}
+	if p.SNI != "" {
+		tls["server_name"] = p.SNI
+	}
+	if p.SkipCertVerify {
+		tls["insecure"] = true
+	}
+	if len(p.Alpn) > 0 {
+		tls["alpn"] = p.Alpn
+	}
+	if p.PublicKey != "" {
+		tls["reality"] = map[string]interface{}{
+			"enabled":    true,
+			"public_key": p.PublicKey,
+			"short_id":   p.ShortID,
+		}
+	}
+	return tls
+}
+
+func (g *SingBoxGenerator) buildTransport(p *proxy.Proxy) map[string]interface{} {
+	switch strings.ToLower(string(p.Network)) {
+	case "ws":
+		return map[string]interface{}{
+			"type": "ws",
+			"path": p.Path,
+			"headers": map[string]string{
+				"Host": p.Host,
+			},
+		}
+	case "grpc":
+		return map[string]interface{}{
+			"type":         "grpc",
+			"service_name": p.ServiceName,
+		}
+	case "h2":
+		return map[string]interface{}{
+			"type": "http",
+			"path": p.Path,
+			"host": []string{p.Host},
+		}
+	default:
+		return nil
+	}
+}
+
+func (g *SingBoxGenerator) buildGroupOutbound(group ProxyGroup) map[string]interface{} {
+	outbound := map[string]interface{}{
+		"tag":       group.Name,
+		"outbounds": group.Proxies,
+	}
+
+	switch group.Type {
+	case "url-test", "fallback":
+		outbound["type"] = "urltest"
+		if group.URL != "" {
+			outbound["url"] = group.URL
+		}
+		if group.Interval > 0 {
+			outbound["interval"] = strconv.Itoa(group.Interval) + "s"
+		}
+	default:
+		outbound["type"] = "selector"
+	}
+
+	return outbound
+}
+
+func (g *SingBoxGenerator) buildRouteRules(rulesets []*ruleset.RuleSet) []map[string]interface{} {
+	var rules []map[string]interface{}
+
+	for _, rs := range rulesets {
+		if !rs.Enabled {
+			continue
+		}
+
+		if rs.Provider != nil {
+			rules = append(rules, map[string]interface{}{
+				"rule_set": []string{rs.Name},
+				"outbound": rs.Policy,
+			})
+			continue
+		}
+
+		for _, rule := range rs.Rules {
+			sbRule := g.buildRouteRule(rule)
+			if sbRule == nil {
+				continue
+			}
+			rules = append(rules, sbRule)
+		}
+	}
+
+	return rules
+}
+
+// buildRuleSets emits a sing-box "route.rule_set" entry for every ruleset
+// backed by a remote RuleProvider, downloaded directly by sing-box itself.
+func (g *SingBoxGenerator) buildRuleSets(rulesets []*ruleset.RuleSet) []map[string]interface{} {
+	var ruleSets []map[string]interface{}
+
+	for _, rs := range rulesets {
+		if rs.Provider == nil {
+			continue
+		}
+
+		format := "source"
+		if rs.Provider.Format == ruleset.FormatMRS {
+			format = "binary"
+		}
+
+		ruleSets = append(ruleSets, map[string]interface{}{
+			"type":            "remote",
+			"tag":             rs.Name,
+			"format":          format,
+			"url":             rs.Provider.URL,
+			"download_detour": "direct",
+		})
+	}
+
+	return ruleSets
+}
+
+func (g *SingBoxGenerator) buildRouteRule(rule ruleset.Rule) map[string]interface{} {
+	outbound := rule.Proxy
+	if outbound == "" {
+		outbound = rule.Policy
+	}
+
+	switch rule.Type {
+	case ruleset.RuleTypeDomain:
+		return map[string]interface{}{"domain": []string{rule.Value}, "outbound": outbound}
+	case ruleset.RuleTypeDomainSuffix:
+		return map[string]interface{}{"domain_suffix": []string{rule.Value}, "outbound": outbound}
+	case ruleset.RuleTypeDomainKeyword:
+		return map[string]interface{}{"domain_keyword": []string{rule.Value}, "outbound": outbound}
+	case ruleset.RuleTypeIPCIDR, ruleset.RuleTypeIPCIDR6:
+		return map[string]interface{}{"ip_cidr": []string{rule.Value}, "outbound": outbound}
+	case ruleset.RuleTypeProcessName:
+		return map[string]interface{}{"process_name": []string{rule.Value}, "outbound": outbound}
+	case ruleset.RuleTypeGeoSite:
+		return map[string]interface{}{"geosite": []string{rule.Value}, "outbound": outbound}
+	case ruleset.RuleTypeGeoIP:
+		return map[string]interface{}{"geoip": []string{rule.Value}, "outbound": outbound}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingBoxGenerator_Generate_Golden(t *testing.T) {
+	generator := NewSingBoxGenerator()
+	ctx := context.Background()
+
+	proxies := []*proxy.Proxy{
+		{
+			Type:     proxy.TypeShadowsocks,
+			Name:     "SS-Node",
+			Server:   "ss.example.com",
+			Port:     8388,
+			Method:   "aes-256-gcm",
+			Password: "secret",
+		},
+	}
+
+	rulesets := []*ruleset.RuleSet{
+		{
+			Enabled: true,
+			Rules: []ruleset.Rule{
+				{Type: ruleset.RuleTypeDomainSuffix, Value: "example.com", Proxy: "SS-Node"},
+				{Type: ruleset.RuleTypeGeoIP, Value: "cn", Proxy: "direct"},
+			},
+		},
+	}
+
+	options := GenerateOptions{
+		ProxyGroups: []ProxyGroup{
+			{Name: "Auto", Type: "select", Proxies: []string{"SS-Node"}},
+		},
+	}
+
+	config, err := generator.Generate(ctx, proxies, rulesets, options)
+	require.NoError(t, err)
+
+	var actual interface{}
+	require.NoError(t, json.Unmarshal([]byte(config), &actual))
+
+	goldenData, err := os.ReadFile("testdata/singbox_golden.json")
+	require.NoError(t, err)
+
+	var expected interface{}
+	require.NoError(t, json.Unmarshal(goldenData, &expected))
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestSingBoxGenerator_RuleProvider(t *testing.T) {
+	generator := NewSingBoxGenerator()
+	ctx := context.Background()
+
+	rulesets := []*ruleset.RuleSet{
+		{
+			Name:    "ad-block",
+			Enabled: true,
+			Policy:  "block",
+			Provider: &ruleset.RuleProvider{
+				URL:      "https://example.com/ad-block.srs",
+				Behavior: ruleset.BehaviorDomain,
+				Format:   ruleset.FormatMRS,
+			},
+		},
+	}
+
+	config, err := generator.Generate(ctx, nil, rulesets, GenerateOptions{})
+	require.NoError(t, err)
+
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(config), &parsed))
+
+	route := parsed["route"].(map[string]interface{})
+
+	ruleSets := route["rule_set"].([]interface{})
+	require.Len(t, ruleSets, 1)
+	rs := ruleSets[0].(map[string]interface{})
+	assert.Equal(t, "remote", rs["type"])
+	assert.Equal(t, "ad-block", rs["tag"])
+	assert.Equal(t, "binary", rs["format"])
+	assert.Equal(t, "https://example.com/ad-block.srs", rs["url"])
+
+	rules := route["rules"].([]interface{})
+	require.Len(t, rules, 1)
+	rule := rules[0].(map[string]interface{})
+	assert.Equal(t, "block", rule["outbound"])
+	assert.Equal(t, []interface{}{"ad-block"}, rule["rule_set"])
+}
+
+func TestSingBoxGenerator_FormatAndContentType(t *testing.T) {
+	generator := NewSingBoxGenerator()
+	assert.Equal(t, "sing-box", generator.Format())
+	assert.Equal(t, "application/json", generator.ContentType())
+}
+
+func TestSingBoxGenerator_UnsupportedProxyIsSkipped(t *testing.T) {
+	generator := NewSingBoxGenerator()
+	ctx := context.Background()
+
+	proxies := []*proxy.Proxy{
+		{Type: proxy.TypeSnell, Name: "Snell-Node", Server: "example.com", Port: 1234},
+	}
+
+	config, err := generator.Generate(ctx, proxies, nil, GenerateOptions{})
+	require.NoError(t, err)
+
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(config), &parsed))
+
+	outbounds := parsed["outbounds"].([]interface{})
+	// Only the default direct/block outbounds remain once the unsupported
+	// Snell proxy is skipped.
+	assert.Len(t, outbounds, 2)
+}
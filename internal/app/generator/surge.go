@@ -55,11 +55,17 @@ func (g *SurgeGenerator) Generate(ctx context.Context, proxies []*proxy.Proxy, r
 	
 	// Rule section
 	builder.WriteString("[Rule]\n")
-	for _, ruleset := range rulesets {
-		if !ruleset.Enabled {
+	for _, rs := range rulesets {
+		if !rs.Enabled {
 			continue
 		}
-		for _, rule := range ruleset.Rules {
+
+		if rs.Provider != nil {
+			builder.WriteString(fmt.Sprintf("RULE-SET,%s,%s\n", rs.Provider.URL, rs.Policy))
+			continue
+		}
+
+		for _, rule := range rs.Rules {
 			line := g.buildRuleLine(rule)
 			builder.WriteString(line)
 			builder.WriteString("\n")
@@ -105,6 +111,79 @@ func (g *SurgeGenerator) buildProxyLine(proxy *proxy.Proxy) string {
 			"password=" + proxy.Password,
 			"tls=true",
 		}
+	case "vless":
+		parts = []string{
+			proxy.Name,
+			"vless",
+			proxy.Server,
+			fmt.Sprintf("%d", proxy.Port),
+			"username=" + proxy.UUID,
+		}
+		if proxy.TLS != "" && proxy.TLS != "none" {
+			parts = append(parts, "tls=true")
+			if proxy.SNI != "" {
+				parts = append(parts, "sni="+proxy.SNI)
+			}
+		}
+		if strings.ToLower(string(proxy.Network)) == "ws" {
+			parts = append(parts, "ws=true", "ws-path="+proxy.Path)
+			if proxy.Host != "" {
+				parts = append(parts, "ws-headers=Host:"+proxy.Host)
+			}
+		}
+	case "hysteria":
+		parts = []string{
+			proxy.Name,
+			"hysteria",
+			proxy.Server,
+			fmt.Sprintf("%d", proxy.Port),
+		}
+		if proxy.Password != "" {
+			parts = append(parts, "auth-str="+proxy.Password)
+		}
+		if proxy.Ports != "" {
+			parts = append(parts, "mport="+proxy.Ports)
+		}
+		if proxy.Protocol != "" {
+			parts = append(parts, "protocol="+proxy.Protocol)
+		}
+		if proxy.UpMbps > 0 {
+			parts = append(parts, fmt.Sprintf("upload-bandwidth=%d", proxy.UpMbps))
+		}
+		if proxy.DownMbps > 0 {
+			parts = append(parts, fmt.Sprintf("download-bandwidth=%d", proxy.DownMbps))
+		}
+		if proxy.SNI != "" {
+			parts = append(parts, "sni="+proxy.SNI)
+		}
+		if proxy.SkipCertVerify {
+			parts = append(parts, "skip-cert-verify=true")
+		}
+	case "hysteria2":
+		parts = []string{
+			proxy.Name,
+			"hysteria2",
+			proxy.Server,
+			fmt.Sprintf("%d", proxy.Port),
+		}
+		if proxy.Password != "" {
+			parts = append(parts, "password="+proxy.Password)
+		}
+		if proxy.Ports != "" {
+			parts = append(parts, "mport="+proxy.Ports)
+		}
+		if proxy.Obfs != "" {
+			parts = append(parts, "obfs="+proxy.Obfs)
+			if proxy.ObfsPassword != "" {
+				parts = append(parts, "obfs-password="+proxy.ObfsPassword)
+			}
+		}
+		if proxy.SNI != "" {
+			parts = append(parts, "sni="+proxy.SNI)
+		}
+		if proxy.SkipCertVerify {
+			parts = append(parts, "skip-cert-verify=true")
+		}
 	default:
 		return fmt.Sprintf("# Unsupported proxy type: %s", proxy.Type)
 	}
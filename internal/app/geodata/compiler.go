@@ -0,0 +1,137 @@
+package geodata
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+	apphttp "github.com/subconverter/subconverter-go/internal/infra/http"
+)
+
+// sourceState records what a source (file path or URL) looked like the
+// last time it was compiled, so a later call can skip recompiling an
+// unchanged source.
+type sourceState struct {
+	mtime string
+	etag  string
+}
+
+// Compiler is the default RulesetCompiler: a GeoSiteDB and an IPTable kept
+// in sync with their on-disk or remote sources, recompiling a source only
+// when its mtime or ETag has changed.
+type Compiler struct {
+	mu      sync.Mutex
+	geosite *GeoSiteDB
+	geoip   *IPTable
+	sources map[string]sourceState
+}
+
+// NewCompiler creates a Compiler with empty GeoSite/GeoIP databases; call
+// LoadGeoIPFile and LoadGeoSiteRemote (or LoadGeoSiteFile) to populate it.
+func NewCompiler() *Compiler {
+	return &Compiler{
+		geosite: NewGeoSiteDB(),
+		geoip:   NewIPTable(),
+		sources: make(map[string]sourceState),
+	}
+}
+
+// LoadCIDRs registers cidrs under country directly, without an MMDB
+// source, for callers that already have their own CIDR lists.
+func (c *Compiler) LoadCIDRs(country string, cidrs []string) error {
+	return c.geoip.LoadCIDRs(country, cidrs)
+}
+
+// LoadGeoIPFile compiles a MaxMind MMDB at path into the GeoIP table,
+// skipping recompilation if path's mtime matches the last successful load.
+func (c *Compiler) LoadGeoIPFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	mtime := info.ModTime().String()
+
+	c.mu.Lock()
+	if state, ok := c.sources[path]; ok && state.mtime == mtime {
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	if err := c.geoip.LoadMMDB(path); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.sources[path] = sourceState{mtime: mtime}
+	c.mu.Unlock()
+	return nil
+}
+
+// LoadGeoSiteFile compiles a local v2ray-format geosite list (one domain
+// rule per line) into tag, skipping recompilation if path's mtime matches
+// the last successful load.
+func (c *Compiler) LoadGeoSiteFile(path, tag string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	mtime := info.ModTime().String()
+
+	c.mu.Lock()
+	if state, ok := c.sources[path]; ok && state.mtime == mtime {
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	c.geosite.Load(tag, strings.Split(string(body), "\n"))
+
+	c.mu.Lock()
+	c.sources[path] = sourceState{mtime: mtime}
+	c.mu.Unlock()
+	return nil
+}
+
+// LoadGeoSiteRemote fetches a v2ray-format geosite list for tag from
+// sourceURL via client, skipping recompilation if the server's ETag
+// matches the last fetch.
+func (c *Compiler) LoadGeoSiteRemote(ctx context.Context, client *apphttp.Client, sourceURL, tag string) error {
+	body, headers, err := client.GetWithHeaders(ctx, sourceURL)
+	if err != nil {
+		return err
+	}
+	etag := headers.Get("ETag")
+
+	c.mu.Lock()
+	if state, ok := c.sources[sourceURL]; ok && etag != "" && state.etag == etag {
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	c.geosite.Load(tag, strings.Split(string(body), "\n"))
+
+	c.mu.Lock()
+	c.sources[sourceURL] = sourceState{etag: etag}
+	c.mu.Unlock()
+	return nil
+}
+
+// ExpandGeoIP implements RulesetCompiler.
+func (c *Compiler) ExpandGeoIP(ctx context.Context, code string) ([]string, error) {
+	return c.geoip.Expand(code)
+}
+
+// ExpandGeoSite implements RulesetCompiler.
+func (c *Compiler) ExpandGeoSite(ctx context.Context, tag string) (ruleset.GeoSiteEntries, error) {
+	return c.geosite.Expand(tag)
+}
+
+var _ RulesetCompiler = (*Compiler)(nil)
@@ -0,0 +1,38 @@
+package geodata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompiler_LoadGeoSiteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cn.txt")
+	require.NoError(t, os.WriteFile(path, []byte("domain:qq.com\nfull:beian.miit.gov.cn\n"), 0o644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.LoadGeoSiteFile(path, "cn"))
+
+	entries, err := compiler.ExpandGeoSite(context.Background(), "cn")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"qq.com"}, entries.Suffixes)
+	assert.Equal(t, []string{"beian.miit.gov.cn"}, entries.Domains)
+
+	// Reloading an unchanged file is a no-op; Expand still returns the
+	// same compiled entries.
+	require.NoError(t, compiler.LoadGeoSiteFile(path, "cn"))
+	entries, err = compiler.ExpandGeoSite(context.Background(), "cn")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"qq.com"}, entries.Suffixes)
+}
+
+func TestCompiler_ExpandGeoIPNotFound(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.ExpandGeoIP(context.Background(), "CN")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
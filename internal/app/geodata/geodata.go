@@ -0,0 +1,31 @@
+// Package geodata compiles MaxMind-style GeoIP databases and v2ray-style
+// GeoSite domain lists into the concrete IP-CIDR/DOMAIN-SUFFIX/
+// DOMAIN-KEYWORD entries that GEOIP/GEOSITE rules reference, so generator
+// targets without a native geo database (like V2Ray) can inline the
+// expansion at generation time instead of passing the tag through.
+package geodata
+
+import (
+	"context"
+	"errors"
+
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+)
+
+// ErrNotFound is returned when a country code or geosite tag has no
+// compiled entries.
+var ErrNotFound = errors.New("geodata: not found")
+
+// RulesetCompiler expands a GEOIP country code or GEOSITE tag into the
+// concrete rule values a generator can inline. Implementations are free to
+// back this with any source (MMDB file, remote geosite list, ...); callers
+// that want another provider can register one by satisfying this interface
+// instead of extending it.
+type RulesetCompiler interface {
+	// ExpandGeoIP returns every CIDR registered to an ISO-3166-1 alpha-2
+	// country code, e.g. "CN".
+	ExpandGeoIP(ctx context.Context, code string) ([]string, error)
+	// ExpandGeoSite returns the compiled domain entries registered to a
+	// geosite tag, e.g. "netflix" or "cn".
+	ExpandGeoSite(ctx context.Context, tag string) (ruleset.GeoSiteEntries, error)
+}
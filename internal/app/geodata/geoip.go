@@ -0,0 +1,144 @@
+package geodata
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// countryPrefix pairs a loaded CIDR with the country it was registered
+// under, for the flattened, longest-prefix-first lookup index.
+type countryPrefix struct {
+	prefix  netip.Prefix
+	country string
+}
+
+// IPTable is an in-memory, net/netip-based longest-prefix table keyed by
+// ISO-3166-1 alpha-2 country code, built either from an explicit CIDR list
+// or compiled from a MaxMind GeoLite2-Country MMDB file.
+type IPTable struct {
+	mu        sync.RWMutex
+	byCountry map[string][]netip.Prefix
+	index     []countryPrefix
+}
+
+// NewIPTable creates an empty IP table.
+func NewIPTable() *IPTable {
+	return &IPTable{byCountry: make(map[string][]netip.Prefix)}
+}
+
+// LoadCIDRs registers every cidr under country, replacing any entries
+// country previously had.
+func (t *IPTable) LoadCIDRs(country string, cidrs []string) error {
+	country = strings.ToUpper(country)
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return fmt.Errorf("geodata: invalid CIDR %q for %s: %w", cidr, country, err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.byCountry[country] = prefixes
+	t.rebuildIndex()
+	return nil
+}
+
+// LoadMMDB compiles every network in a MaxMind GeoLite2-Country (or
+// GeoIP2-Country) database at path into the table, replacing all
+// previously loaded entries.
+func (t *IPTable) LoadMMDB(path string) error {
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return fmt.Errorf("geodata: failed to open MMDB %q: %w", path, err)
+	}
+	defer db.Close()
+
+	var record struct {
+		Country struct {
+			ISOCode string `maxminddb:"iso_code"`
+		} `maxminddb:"country"`
+	}
+
+	byCountry := make(map[string][]netip.Prefix)
+	networks := db.Networks()
+	for networks.Next() {
+		subnet, err := networks.Network(&record)
+		if err != nil {
+			return fmt.Errorf("geodata: failed to decode MMDB network: %w", err)
+		}
+		if record.Country.ISOCode == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(subnet.String())
+		if err != nil {
+			continue
+		}
+		byCountry[record.Country.ISOCode] = append(byCountry[record.Country.ISOCode], prefix)
+	}
+	if err := networks.Err(); err != nil {
+		return fmt.Errorf("geodata: error iterating MMDB networks: %w", err)
+	}
+
+	t.mu.Lock()
+	t.byCountry = byCountry
+	t.rebuildIndex()
+	t.mu.Unlock()
+	return nil
+}
+
+// rebuildIndex flattens byCountry into index, sorted by prefix length
+// descending so Lookup's first match is the most specific one. Callers
+// must hold mu.
+func (t *IPTable) rebuildIndex() {
+	index := make([]countryPrefix, 0)
+	for country, prefixes := range t.byCountry {
+		for _, prefix := range prefixes {
+			index = append(index, countryPrefix{prefix: prefix, country: country})
+		}
+	}
+	sort.Slice(index, func(i, j int) bool {
+		return index[i].prefix.Bits() > index[j].prefix.Bits()
+	})
+	t.index = index
+}
+
+// Lookup returns the ISO-3166-1 alpha-2 country code of the most specific
+// loaded prefix containing addr.
+func (t *IPTable) Lookup(addr netip.Addr) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, entry := range t.index {
+		if entry.prefix.Contains(addr) {
+			return entry.country, true
+		}
+	}
+	return "", false
+}
+
+// Expand returns every CIDR registered to country, or ErrNotFound if
+// country hasn't been loaded.
+func (t *IPTable) Expand(country string) ([]string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	prefixes, ok := t.byCountry[strings.ToUpper(country)]
+	if !ok {
+		return nil, fmt.Errorf("%w: country code %q", ErrNotFound, country)
+	}
+
+	cidrs := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		cidrs[i] = prefix.String()
+	}
+	return cidrs, nil
+}
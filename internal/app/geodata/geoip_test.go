@@ -0,0 +1,44 @@
+package geodata
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPTable_LoadCIDRsAndExpand(t *testing.T) {
+	table := NewIPTable()
+	require.NoError(t, table.LoadCIDRs("cn", []string{"1.0.1.0/24", "1.0.2.0/23"}))
+
+	cidrs, err := table.Expand("CN")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1.0.1.0/24", "1.0.2.0/23"}, cidrs)
+
+	_, err = table.Expand("US")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestIPTable_LoadCIDRsInvalid(t *testing.T) {
+	table := NewIPTable()
+	err := table.LoadCIDRs("cn", []string{"not-a-cidr"})
+	assert.Error(t, err)
+}
+
+func TestIPTable_LookupPrefersLongestPrefix(t *testing.T) {
+	table := NewIPTable()
+	require.NoError(t, table.LoadCIDRs("cn", []string{"1.0.0.0/8"}))
+	require.NoError(t, table.LoadCIDRs("us", []string{"1.0.1.0/24"}))
+
+	country, ok := table.Lookup(netip.MustParseAddr("1.0.1.5"))
+	require.True(t, ok)
+	assert.Equal(t, "US", country)
+
+	country, ok = table.Lookup(netip.MustParseAddr("1.0.2.5"))
+	require.True(t, ok)
+	assert.Equal(t, "CN", country)
+
+	_, ok = table.Lookup(netip.MustParseAddr("8.8.8.8"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,180 @@
+package geodata
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+)
+
+// trieNode is one label of a reverse-label domain trie; a terminal node
+// marks a registered suffix.
+type trieNode struct {
+	children map[string]*trieNode
+	terminal bool
+}
+
+// domainTrie matches a domain against a set of registered suffixes by
+// walking its labels in reverse (TLD first), so "a.b.example.com" matches
+// a registered "example.com" without scanning every suffix individually.
+type domainTrie struct {
+	root *trieNode
+}
+
+func newDomainTrie() *domainTrie {
+	return &domainTrie{root: &trieNode{children: make(map[string]*trieNode)}}
+}
+
+func (t *domainTrie) insert(suffix string) {
+	node := t.root
+	for _, label := range reverseLabels(suffix) {
+		child, ok := node.children[label]
+		if !ok {
+			child = &trieNode{children: make(map[string]*trieNode)}
+			node.children[label] = child
+		}
+		node = child
+	}
+	node.terminal = true
+}
+
+// match reports whether domain equals, or is a subdomain of, any suffix
+// inserted into the trie.
+func (t *domainTrie) match(domain string) bool {
+	node := t.root
+	for _, label := range reverseLabels(domain) {
+		child, ok := node.children[label]
+		if !ok {
+			return false
+		}
+		if child.terminal {
+			return true
+		}
+		node = child
+	}
+	return false
+}
+
+func reverseLabels(domain string) []string {
+	labels := strings.Split(strings.ToLower(domain), ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return labels
+}
+
+// geoSiteSet is one geosite tag's compiled entries: a suffix trie, a
+// keyword substring index, and an exact-match hash.
+type geoSiteSet struct {
+	suffixTrie *domainTrie
+	suffixes   []string
+	keywords   []string
+	exact      map[string]bool
+	exactList  []string
+}
+
+func newGeoSiteSet() *geoSiteSet {
+	return &geoSiteSet{suffixTrie: newDomainTrie(), exact: make(map[string]bool)}
+}
+
+func (s *geoSiteSet) addSuffix(domain string) {
+	s.suffixTrie.insert(domain)
+	s.suffixes = append(s.suffixes, domain)
+}
+
+func (s *geoSiteSet) addKeyword(keyword string) {
+	s.keywords = append(s.keywords, keyword)
+}
+
+func (s *geoSiteSet) addExact(domain string) {
+	domain = strings.ToLower(domain)
+	if s.exact[domain] {
+		return
+	}
+	s.exact[domain] = true
+	s.exactList = append(s.exactList, domain)
+}
+
+// match reports whether domain is covered by any rule kind in the set.
+func (s *geoSiteSet) match(domain string) bool {
+	domain = strings.ToLower(domain)
+	if s.exact[domain] {
+		return true
+	}
+	if s.suffixTrie.match(domain) {
+		return true
+	}
+	for _, keyword := range s.keywords {
+		if strings.Contains(domain, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// GeoSiteDB is an in-memory compiled geosite database keyed by tag, each
+// tag holding its own domain trie, keyword index, and exact-match hash.
+type GeoSiteDB struct {
+	mu   sync.RWMutex
+	tags map[string]*geoSiteSet
+}
+
+// NewGeoSiteDB creates an empty geosite database.
+func NewGeoSiteDB() *GeoSiteDB {
+	return &GeoSiteDB{tags: make(map[string]*geoSiteSet)}
+}
+
+// Load compiles tag's entries from the v2ray geosite text format: one
+// "full:value" (exact), "keyword:value", or "domain:value" (suffix) per
+// line, defaulting to a suffix match when no prefix is given. Blank lines
+// and "#" comments are skipped. Load replaces any entries tag already had.
+func (db *GeoSiteDB) Load(tag string, lines []string) {
+	set := newGeoSiteSet()
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "full:"):
+			set.addExact(strings.TrimPrefix(line, "full:"))
+		case strings.HasPrefix(line, "keyword:"):
+			set.addKeyword(strings.TrimPrefix(line, "keyword:"))
+		case strings.HasPrefix(line, "domain:"):
+			set.addSuffix(strings.TrimPrefix(line, "domain:"))
+		default:
+			set.addSuffix(line)
+		}
+	}
+
+	db.mu.Lock()
+	db.tags[strings.ToLower(tag)] = set
+	db.mu.Unlock()
+}
+
+// Expand returns tag's compiled entries, or ErrNotFound if tag hasn't
+// been loaded.
+func (db *GeoSiteDB) Expand(tag string) (ruleset.GeoSiteEntries, error) {
+	db.mu.RLock()
+	set, ok := db.tags[strings.ToLower(tag)]
+	db.mu.RUnlock()
+	if !ok {
+		return ruleset.GeoSiteEntries{}, fmt.Errorf("%w: geosite tag %q", ErrNotFound, tag)
+	}
+
+	return ruleset.GeoSiteEntries{
+		Domains:  append([]string(nil), set.exactList...),
+		Suffixes: append([]string(nil), set.suffixes...),
+		Keywords: append([]string(nil), set.keywords...),
+	}, nil
+}
+
+// Match reports whether domain is covered by tag's compiled entries.
+func (db *GeoSiteDB) Match(tag, domain string) bool {
+	db.mu.RLock()
+	set, ok := db.tags[strings.ToLower(tag)]
+	db.mu.RUnlock()
+	return ok && set.match(domain)
+}
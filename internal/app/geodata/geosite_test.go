@@ -0,0 +1,51 @@
+package geodata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoSiteDB_ExpandAndMatch(t *testing.T) {
+	db := NewGeoSiteDB()
+	db.Load("cn", []string{
+		"# comment",
+		"",
+		"full:beian.miit.gov.cn",
+		"keyword:icbc",
+		"domain:qq.com",
+		"baidu.com",
+	})
+
+	entries, err := db.Expand("cn")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"beian.miit.gov.cn"}, entries.Domains)
+	assert.Equal(t, []string{"icbc"}, entries.Keywords)
+	assert.ElementsMatch(t, []string{"qq.com", "baidu.com"}, entries.Suffixes)
+
+	assert.True(t, db.Match("cn", "beian.miit.gov.cn"))
+	assert.True(t, db.Match("cn", "www.qq.com"))
+	assert.True(t, db.Match("cn", "mail.baidu.com"))
+	assert.True(t, db.Match("cn", "icbc-bank.com"))
+	assert.False(t, db.Match("cn", "example.com"))
+
+	// Tag lookup is case-insensitive.
+	assert.True(t, db.Match("CN", "qq.com"))
+}
+
+func TestGeoSiteDB_ExpandNotFound(t *testing.T) {
+	db := NewGeoSiteDB()
+	_, err := db.Expand("does-not-exist")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDomainTrie_SuffixOnlyMatchesSubdomains(t *testing.T) {
+	trie := newDomainTrie()
+	trie.insert("example.com")
+
+	assert.True(t, trie.match("example.com"))
+	assert.True(t, trie.match("api.example.com"))
+	assert.False(t, trie.match("notexample.com"))
+	assert.False(t, trie.match("example.org"))
+}
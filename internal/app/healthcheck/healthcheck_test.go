@@ -0,0 +1,95 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/subconverter/subconverter-go/internal/app/adapter"
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
+)
+
+// listen starts a bare TCP listener that accepts and immediately closes
+// connections, so probes against it succeed without a real proxy server.
+func listen(t *testing.T) (host string, port int) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestProber_MeasureReachable(t *testing.T) {
+	host, port := listen(t)
+	prober := NewProber(cache.NewMemoryCache(), time.Minute, time.Second, 4)
+
+	proxies := []*proxy.Proxy{
+		{Type: proxy.TypeShadowsocks, Server: host, Port: port},
+	}
+
+	prober.Measure(context.Background(), proxies)
+
+	assert.True(t, proxies[0].Alive)
+	assert.GreaterOrEqual(t, proxies[0].Latency, int64(0))
+}
+
+func TestProber_MeasureUnreachable(t *testing.T) {
+	prober := NewProber(cache.NewMemoryCache(), time.Minute, 100*time.Millisecond, 4)
+
+	proxies := []*proxy.Proxy{
+		{Type: proxy.TypeShadowsocks, Server: "192.0.2.1", Port: 1},
+	}
+
+	prober.Measure(context.Background(), proxies)
+
+	assert.False(t, proxies[0].Alive)
+	assert.Equal(t, int64(0), proxies[0].Latency)
+}
+
+func TestProber_ResultIsCached(t *testing.T) {
+	host, port := listen(t)
+	store := cache.NewMemoryCache()
+	prober := NewProber(store, time.Minute, time.Second, 4)
+
+	p := &proxy.Proxy{Type: proxy.TypeShadowsocks, Server: host, Port: port}
+	prober.Measure(context.Background(), []*proxy.Proxy{p})
+	require.True(t, p.Alive)
+
+	cached, err := store.Get(context.Background(), "probe:"+string(p.Type)+":"+host+":"+strconv.Itoa(port))
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(cached), `"alive":true`))
+}
+
+func TestProber_SetTrafficController_RoutesProbeConnection(t *testing.T) {
+	host, port := listen(t)
+	prober := NewProber(cache.NewMemoryCache(), time.Minute, time.Second, 4)
+	tc := adapter.NewController()
+	prober.SetTrafficController(tc)
+
+	p := &proxy.Proxy{ID: "proxy-1", Type: proxy.TypeShadowsocks, Server: host, Port: port}
+	prober.Measure(context.Background(), []*proxy.Proxy{p})
+
+	require.True(t, p.Alive)
+	_, ok := tc.Tracker("proxy-1")
+	assert.True(t, ok)
+}
@@ -0,0 +1,41 @@
+package healthcheck
+
+import (
+	"sync"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+)
+
+// StatsStore records the last proxy.Statistics measured for each proxy
+// ID, independent of the *proxy.Proxy values Measure was called with -
+// useful once a caller holds proxies by ID (a persisted subscription's
+// entries, say) rather than the exact slice that was probed.
+//
+// Measure only ever populates Latency: this package's probe is a plain
+// TCP connect (see the package doc comment), so there is no per-protocol
+// dial transport yet to carry real traffic through a proxy and measure
+// Download/Upload. Those fields stay zero until such a dialer exists.
+type StatsStore struct {
+	mu    sync.RWMutex
+	stats map[string]proxy.Statistics
+}
+
+// NewStatsStore builds an empty StatsStore.
+func NewStatsStore() *StatsStore {
+	return &StatsStore{stats: make(map[string]proxy.Statistics)}
+}
+
+// Set records stats for id, overwriting any previous measurement.
+func (s *StatsStore) Set(id string, stats proxy.Statistics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats[id] = stats
+}
+
+// Get returns the last statistics recorded for id, if any.
+func (s *StatsStore) Get(id string) (proxy.Statistics, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats, ok := s.stats[id]
+	return stats, ok
+}
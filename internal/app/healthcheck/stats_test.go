@@ -0,0 +1,21 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+)
+
+func TestStatsStore_SetGet(t *testing.T) {
+	store := NewStatsStore()
+
+	_, ok := store.Get("missing")
+	assert.False(t, ok)
+
+	store.Set("proxy-1", proxy.Statistics{Latency: 42})
+	stats, ok := store.Get("proxy-1")
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), stats.Latency)
+}
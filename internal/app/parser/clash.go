@@ -31,6 +31,15 @@ func (p *ClashParser) Support(content string) bool {
 	return strings.Contains(content, "proxies:")
 }
 
+// Detect reports how confident ClashParser is that content is a Clash
+// configuration, so Manager can pick the best match among whole-file parsers.
+func (p *ClashParser) Detect(content string) (Confidence, error) {
+	if !p.Support(content) {
+		return ConfidenceNone, nil
+	}
+	return ConfidenceHigh, nil
+}
+
 // Parse parses the Clash configuration and extracts proxies.
 func (p *ClashParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
 	var config struct {
@@ -108,12 +117,18 @@ func (p *ClashParser) parseProxyMap(proxyMap map[string]interface{}) (*proxy.Pro
 	case "vless":
 		node.UUID = lo.ValueOr(proxyMap, "uuid", "").(string)
 		node.Network = proxy.Network(lo.ValueOr(proxyMap, "network", "tcp").(string))
+		node.Flow = lo.ValueOr(proxyMap, "flow", "").(string)
 		if tls, ok := proxyMap["tls"].(bool); ok && tls {
 			node.TLS = proxy.TLSRequire
 		}
 		node.SNI = lo.ValueOr(proxyMap, "servername", "").(string)
+		node.Fingerprint = lo.ValueOr(proxyMap, "client-fingerprint", "").(string)
+		if realityOpts, ok := proxyMap["reality-opts"].(map[string]interface{}); ok {
+			node.PublicKey = lo.ValueOr(realityOpts, "public-key", "").(string)
+			node.ShortID = lo.ValueOr(realityOpts, "short-id", "").(string)
+		}
 		if grpcOpts, ok := proxyMap["grpc-opts"].(map[string]interface{}); ok {
-			node.Path = lo.ValueOr(grpcOpts, "grpc-service-name", "").(string)
+			node.ServiceName = lo.ValueOr(grpcOpts, "grpc-service-name", "").(string)
 		}
 	case "trojan":
 		node.Password = lo.ValueOr(proxyMap, "password", "").(string)
@@ -131,6 +146,11 @@ func (p *ClashParser) parseProxyMap(proxyMap map[string]interface{}) (*proxy.Pro
 		node.Password = lo.ValueOr(proxyMap, "password", "").(string)
 		node.SNI = lo.ValueOr(proxyMap, "sni", "").(string)
 		node.SkipCertVerify = lo.ValueOr(proxyMap, "skip-cert-verify", false).(bool)
+	case "ssh":
+		node.Username = lo.ValueOr(proxyMap, "username", "").(string)
+		node.Password = lo.ValueOr(proxyMap, "password", "").(string)
+		node.PrivateKey = lo.ValueOr(proxyMap, "private-key", "").(string)
+		node.PrivateKeyPassphrase = lo.ValueOr(proxyMap, "private-key-passphrase", "").(string)
 	default:
 		return nil, fmt.Errorf("unsupported proxy type: %s", node.Type)
 	}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"mime/quotedprintable"
+	"net/url"
+	"strings"
+)
+
+// ContentDecoder attempts to decode raw subscription content (e.g. Base64 or
+// gzip-compressed bodies) before format detection and parsing take place.
+// Decoders are tried in order; the first one that reports ok=true wins.
+type ContentDecoder interface {
+	// Decode attempts to decode content, returning the decoded result and
+	// whether decoding succeeded.
+	Decode(content string) (decoded string, ok bool)
+	// Name identifies the decoder for logging/diagnostics.
+	Name() string
+}
+
+// Base64Decoder decodes standard Base64-encoded subscription content.
+type Base64Decoder struct{}
+
+func NewBase64Decoder() *Base64Decoder { return &Base64Decoder{} }
+func (d *Base64Decoder) Name() string  { return "base64" }
+func (d *Base64Decoder) Decode(content string) (string, bool) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(content))
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// Base64URLDecoder decodes URL-safe, unpadded Base64 subscription content.
+type Base64URLDecoder struct{}
+
+func NewBase64URLDecoder() *Base64URLDecoder { return &Base64URLDecoder{} }
+func (d *Base64URLDecoder) Name() string     { return "base64url" }
+func (d *Base64URLDecoder) Decode(content string) (string, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(content))
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// GzipDecoder decodes gzip-compressed subscription content.
+type GzipDecoder struct{}
+
+func NewGzipDecoder() *GzipDecoder { return &GzipDecoder{} }
+func (d *GzipDecoder) Name() string { return "gzip" }
+func (d *GzipDecoder) Decode(content string) (string, bool) {
+	reader, err := gzip.NewReader(strings.NewReader(content))
+	if err != nil {
+		return "", false
+	}
+	defer reader.Close()
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// QuotedPrintableDecoder decodes quoted-printable subscription content.
+type QuotedPrintableDecoder struct{}
+
+func NewQuotedPrintableDecoder() *QuotedPrintableDecoder { return &QuotedPrintableDecoder{} }
+func (d *QuotedPrintableDecoder) Name() string            { return "quoted-printable" }
+func (d *QuotedPrintableDecoder) Decode(content string) (string, bool) {
+	// Only attempt this decoder when the content actually exhibits the
+	// soft-line-break convention; bare "=" is too common in ordinary proxy
+	// URIs (query strings) to use as a trigger on its own.
+	if !strings.Contains(content, "=\n") && !strings.Contains(content, "=\r\n") {
+		return "", false
+	}
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(content)))
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// URLDecoder decodes percent-encoded subscription content.
+type URLDecoder struct{}
+
+func NewURLDecoder() *URLDecoder { return &URLDecoder{} }
+func (d *URLDecoder) Name() string { return "url-encoded" }
+func (d *URLDecoder) Decode(content string) (string, bool) {
+	if !strings.Contains(content, "%") {
+		return "", false
+	}
+	decoded, err := url.QueryUnescape(content)
+	if err != nil {
+		return "", false
+	}
+	return decoded, true
+}
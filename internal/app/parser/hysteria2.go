@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+)
+
+// Hysteria2Parser parses Hysteria2 protocol URLs of the form
+// hysteria2://password@host:port/?obfs=salamander&obfs-password=...&sni=...#name
+type Hysteria2Parser struct{}
+
+func NewHysteria2Parser() *Hysteria2Parser {
+	return &Hysteria2Parser{}
+}
+
+func (p *Hysteria2Parser) Type() proxy.Type {
+	return proxy.TypeHysteria2
+}
+
+func (p *Hysteria2Parser) Support(content string) bool {
+	return strings.HasPrefix(content, "hysteria2://") || strings.HasPrefix(content, "hy2://")
+}
+
+func (p *Hysteria2Parser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
+	if !p.Support(content) {
+		return nil, fmt.Errorf("%w: invalid hysteria2 URL format", errors.ErrParseFailed)
+	}
+
+	u, err := url.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse hysteria2 URL: %v", errors.ErrParseFailed, err)
+	}
+
+	host, port, ports, err := splitHysteria2HostPort(u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid hysteria2 host/port: %v", errors.ErrParseFailed, err)
+	}
+
+	// sub2clash historically left the name empty when the fragment was
+	// missing; default it to the server address instead.
+	name := u.Fragment
+	if name == "" {
+		name = fmt.Sprintf("Hysteria2-%s", host)
+	}
+
+	query := u.Query()
+
+	result := &proxy.Proxy{
+		ID:           uuid.New().String(),
+		Type:         proxy.TypeHysteria2,
+		Name:         name,
+		Server:       host,
+		Port:         port,
+		Ports:        ports,
+		Password:     u.User.Username(),
+		SNI:          query.Get("sni"),
+		Obfs:         query.Get("obfs"),
+		ObfsPassword: query.Get("obfs-password"),
+		PinSHA256:    query.Get("pinSHA256"),
+		UpMbps:       parseHysteriaBandwidth(query.Get("up")),
+		DownMbps:     parseHysteriaBandwidth(query.Get("down")),
+		UDP:          true,
+	}
+
+	if interval := query.Get("hop-interval"); interval != "" {
+		if seconds, err := strconv.Atoi(interval); err == nil {
+			result.HopInterval = seconds
+		}
+	}
+
+	if insecure := query.Get("insecure"); insecure == "1" || strings.EqualFold(insecure, "true") {
+		result.SkipCertVerify = true
+	}
+
+	if fastopen := query.Get("fastopen"); fastopen == "1" || strings.EqualFold(fastopen, "true") {
+		result.FastOpen = true
+	}
+
+	return []*proxy.Proxy{result}, nil
+}
+
+// splitHysteria2HostPort splits a hysteria2 authority into its host, primary
+// port (used for the initial handshake) and, when the URI specifies a
+// port-hopping range such as "20000-40000,50000", the raw ports string.
+func splitHysteria2HostPort(authority string) (host string, port int, ports string, err error) {
+	host, portField, err := net.SplitHostPort(authority)
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	if strings.ContainsAny(portField, ",-") {
+		ports = portField
+		portField = portField[:strings.IndexAny(portField, ",-")]
+	}
+
+	port, err = strconv.Atoi(portField)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("invalid port %q: %v", portField, err)
+	}
+
+	return host, port, ports, nil
+}
+
+// parseHysteriaBandwidth extracts the leading integer from a Hysteria
+// bandwidth string such as "100 mbps", returning 0 if it can't be parsed.
+func parseHysteriaBandwidth(s string) int {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHysteria2Parser_Parse(t *testing.T) {
+	parser := NewHysteria2Parser()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected *proxy.Proxy
+		wantErr  bool
+	}{
+		{
+			name:  "full parameter coverage",
+			input: "hysteria2://secret@example.com:443/?obfs=salamander&obfs-password=obfspass&sni=example.com&insecure=1&fastopen=1&pinSHA256=AA%3ABB&up=100+mbps&down=200+mbps&hop-interval=30#My-Node",
+			expected: &proxy.Proxy{
+				Type:           proxy.TypeHysteria2,
+				Server:         "example.com",
+				Port:           443,
+				Password:       "secret",
+				SNI:            "example.com",
+				Obfs:           "salamander",
+				ObfsPassword:   "obfspass",
+				PinSHA256:      "AA:BB",
+				UpMbps:         100,
+				DownMbps:       200,
+				HopInterval:    30,
+				SkipCertVerify: true,
+				FastOpen:       true,
+				Name:           "My-Node",
+			},
+		},
+		{
+			name:  "port hopping range",
+			input: "hysteria2://secret@example.com:20000-40000,50000/#Hopper",
+			expected: &proxy.Proxy{
+				Type:     proxy.TypeHysteria2,
+				Server:   "example.com",
+				Port:     20000,
+				Ports:    "20000-40000,50000",
+				Password: "secret",
+				Name:     "Hopper",
+			},
+		},
+		{
+			name:  "name defaults to server when fragment is missing",
+			input: "hysteria2://secret@example.com:443/",
+			expected: &proxy.Proxy{
+				Type:     proxy.TypeHysteria2,
+				Server:   "example.com",
+				Port:     443,
+				Password: "secret",
+				Name:     "Hysteria2-example.com",
+			},
+		},
+		{
+			name:    "invalid format",
+			input:   "invalid://test",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(ctx, tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+
+			p := result[0]
+			assert.Equal(t, tt.expected.Type, p.Type)
+			assert.Equal(t, tt.expected.Server, p.Server)
+			assert.Equal(t, tt.expected.Port, p.Port)
+			assert.Equal(t, tt.expected.Ports, p.Ports)
+			assert.Equal(t, tt.expected.Password, p.Password)
+			assert.Equal(t, tt.expected.SNI, p.SNI)
+			assert.Equal(t, tt.expected.Obfs, p.Obfs)
+			assert.Equal(t, tt.expected.ObfsPassword, p.ObfsPassword)
+			assert.Equal(t, tt.expected.PinSHA256, p.PinSHA256)
+			assert.Equal(t, tt.expected.UpMbps, p.UpMbps)
+			assert.Equal(t, tt.expected.DownMbps, p.DownMbps)
+			assert.Equal(t, tt.expected.HopInterval, p.HopInterval)
+			assert.Equal(t, tt.expected.SkipCertVerify, p.SkipCertVerify)
+			assert.Equal(t, tt.expected.FastOpen, p.FastOpen)
+			assert.Equal(t, tt.expected.Name, p.Name)
+		})
+	}
+}
+
+func TestHysteria2Parser_Support(t *testing.T) {
+	parser := NewHysteria2Parser()
+
+	assert.True(t, parser.Support("hysteria2://test"))
+	assert.True(t, parser.Support("hy2://test"))
+	assert.False(t, parser.Support("hysteria://test"))
+	assert.False(t, parser.Support("invalid://test"))
+}
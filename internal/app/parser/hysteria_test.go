@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHysteriaParser_Parse(t *testing.T) {
+	parser := NewHysteriaParser()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected *proxy.Proxy
+		wantErr  bool
+	}{
+		{
+			name:  "full parameter coverage",
+			input: "hysteria://example.com:443?auth=secret&protocol=udp&peer=example.com&insecure=1&upmbps=10&downmbps=50&obfs=xplus&alpn=h3,http/1.1#My-Node",
+			expected: &proxy.Proxy{
+				Type:           proxy.Type("hysteria"),
+				Server:         "example.com",
+				Port:           443,
+				Password:       "secret",
+				Protocol:       "udp",
+				SNI:            "example.com",
+				Obfs:           "xplus",
+				Alpn:           []string{"h3", "http/1.1"},
+				UpMbps:         10,
+				DownMbps:       50,
+				SkipCertVerify: true,
+				Name:           "My-Node",
+			},
+		},
+		{
+			name:  "mport port-hopping range",
+			input: "hysteria://secret@example.com:443?mport=20000-30000#Hopper",
+			expected: &proxy.Proxy{
+				Type:     proxy.Type("hysteria"),
+				Server:   "example.com",
+				Port:     443,
+				Password: "secret",
+				Ports:    "20000-30000",
+				Name:     "Hopper",
+			},
+		},
+		{
+			name:  "name defaults to server when fragment is missing",
+			input: "hysteria://example.com:443",
+			expected: &proxy.Proxy{
+				Type:   proxy.Type("hysteria"),
+				Server: "example.com",
+				Port:   443,
+				Name:   "Hysteria-example.com",
+			},
+		},
+		{
+			name:    "invalid format",
+			input:   "invalid://test",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(ctx, tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+
+			p := result[0]
+			assert.Equal(t, tt.expected.Type, p.Type)
+			assert.Equal(t, tt.expected.Server, p.Server)
+			assert.Equal(t, tt.expected.Port, p.Port)
+			assert.Equal(t, tt.expected.Ports, p.Ports)
+			assert.Equal(t, tt.expected.Password, p.Password)
+			assert.Equal(t, tt.expected.Protocol, p.Protocol)
+			assert.Equal(t, tt.expected.SNI, p.SNI)
+			assert.Equal(t, tt.expected.Obfs, p.Obfs)
+			assert.Equal(t, tt.expected.Alpn, p.Alpn)
+			assert.Equal(t, tt.expected.UpMbps, p.UpMbps)
+			assert.Equal(t, tt.expected.DownMbps, p.DownMbps)
+			assert.Equal(t, tt.expected.SkipCertVerify, p.SkipCertVerify)
+			assert.Equal(t, tt.expected.Name, p.Name)
+			assert.True(t, p.UDP)
+		})
+	}
+}
+
+func TestHysteriaParser_Support(t *testing.T) {
+	parser := NewHysteriaParser()
+
+	assert.True(t, parser.Support("hysteria://test"))
+	assert.False(t, parser.Support("hysteria2://test"))
+	assert.False(t, parser.Support("invalid://test"))
+}
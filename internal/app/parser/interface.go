@@ -2,11 +2,13 @@ package parser
 
 import (
 	"context"
-	"encoding/base64"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
 	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+	"github.com/subconverter/subconverter-go/internal/pkg/middleware"
 )
 
 // Parser defines the interface for parsing different proxy protocols
@@ -21,18 +23,126 @@ type Parser interface {
 	Type() proxy.Type
 }
 
+// LineParser names Parser's role explicitly: a parser that handles one proxy
+// link per line (ss://, vmess://, trojan://, ...), as opposed to a
+// WholeFileParser that consumes the entire subscription body at once.
+type LineParser = Parser
+
+// Confidence expresses how sure a WholeFileParser is that it can handle a
+// given piece of content, so Manager can resolve ambiguous input (e.g. YAML
+// that could be Clash or Stash) by picking the highest-confidence match
+// instead of relying on registration order.
+type Confidence int
+
+const (
+	ConfidenceNone   Confidence = 0
+	ConfidenceLow    Confidence = 1
+	ConfidenceMedium Confidence = 2
+	ConfidenceHigh   Confidence = 3
+)
+
+// WholeFileParser parses subscription formats that span the entire content
+// block rather than one link per line, e.g. Clash YAML configs.
+type WholeFileParser interface {
+	// Parse parses the whole content block into proxy configurations.
+	Parse(ctx context.Context, content string) ([]*proxy.Proxy, error)
+
+	// Detect reports how confident this parser is that it can handle content.
+	Detect(content string) (Confidence, error)
+
+	// Type returns the type of proxy this parser handles.
+	Type() proxy.Type
+}
+
+// LineError describes why a single line of a subscription failed to parse.
+type LineError struct {
+	LineNumber int
+	RawLine    string
+	ParserType string
+	Err        error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d (%s): %v", e.LineNumber, e.ParserType, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError aggregates the LineErrors produced by a single Parse call in
+// strict mode.
+type MultiError struct {
+	Errors []*LineError
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d lines failed to parse, first: %v", len(e.Errors), e.Errors[0])
+}
+
+// ParseOptions configures how Manager.Parse handles malformed input.
+type ParseOptions struct {
+	// Strict causes Parse to reject the whole subscription (returning a
+	// *MultiError) instead of silently dropping unparseable lines.
+	Strict bool
+	// MaxErrors caps the number of diagnostics collected, 0 means unlimited.
+	MaxErrors int
+	// CollectDiagnostics surfaces per-line diagnostics even in non-strict
+	// mode, so callers (e.g. the HTTP layer) can report parse warnings.
+	CollectDiagnostics bool
+}
+
+// DefaultParseOptions mirrors the manager's historical best-effort behavior:
+// drop bad lines, log a warning, and don't bother collecting diagnostics.
+func DefaultParseOptions() ParseOptions {
+	return ParseOptions{}
+}
+
+// ParseHook is invoked after every individual parser.Parse attempt (whole-file
+// or per-line), letting operators plug in OpenTelemetry spans or structured
+// audit logs without modifying Manager itself.
+type ParseHook func(ctx context.Context, parserType proxy.Type, rawLine string, duration time.Duration, err error)
+
 // Manager manages multiple parsers and dispatches parsing tasks
 type Manager struct {
-	parsers []Parser
-	logger  *logger.Logger
+	parsers          []LineParser
+	wholeFileParsers []WholeFileParser
+	decoders         []ContentDecoder
+	logger           *logger.Logger
+	options          ParseOptions
+	hook             ParseHook
+}
+
+// SetParseHook installs a hook called after every parse attempt.
+func (m *Manager) SetParseHook(hook ParseHook) {
+	m.hook = hook
 }
 
 // NewManager creates a new parser manager with all available parsers
 func NewManager(log *logger.Logger) *Manager {
+	return NewManagerWithOptions(log, DefaultParseOptions())
+}
+
+// NewManagerWithOptions creates a new parser manager with explicit parse
+// behavior (strict mode, diagnostics collection, error caps).
+func NewManagerWithOptions(log *logger.Logger, options ParseOptions) *Manager {
 	return &Manager{
-		logger: log,
-		parsers: []Parser{
+		logger:  log,
+		options: options,
+		decoders: []ContentDecoder{
+			NewBase64Decoder(),
+			NewBase64URLDecoder(),
+			NewGzipDecoder(),
+			NewQuotedPrintableDecoder(),
+			NewURLDecoder(),
+		},
+		wholeFileParsers: []WholeFileParser{
 			NewClashParser(),
+		},
+		parsers: []LineParser{
 			NewSSParser(),
 			NewSSRParser(),
 			NewVMessParser(),
@@ -43,65 +153,142 @@ func NewManager(log *logger.Logger) *Manager {
 			NewSnellParser(),
 			NewHTTPParser(),
 			NewSocks5Parser(),
+			NewSSHParser(),
 		},
 	}
 }
 
-// Parse parses subscription content using appropriate parser
-func (m *Manager) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
-	// Attempt to decode Base64 content, as many subscriptions are encoded this way.
+// Parse parses subscription content using the appropriate parser.
+//
+// The returned []*LineError carries per-line diagnostics: always populated
+// in strict mode (alongside a non-nil *MultiError as the error return), and
+// populated in non-strict mode only when Options.CollectDiagnostics is set.
+func (m *Manager) Parse(ctx context.Context, content string) ([]*proxy.Proxy, []*LineError, error) {
+	// Run the decoder chain; the first decoder that succeeds wins, since many
+	// subscriptions are encoded with exactly one of these schemes.
 	processedContent := content
-	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(content)); err == nil {
-		processedContent = string(decoded)
-	} else if decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(content)); err == nil {
-		processedContent = string(decoded)
+	for _, decoder := range m.decoders {
+		if decoded, ok := decoder.Decode(content); ok {
+			processedContent = decoded
+			break
+		}
 	}
 
-	// Stage 1: Try to find a parser that can handle the entire content block.
+	// Stage 1: Resolve the whole-file parser with the highest confidence.
 	// This is for file-based formats like Clash, which are not line-based.
-	for _, parser := range m.parsers {
-		// Heuristic to identify whole-file parsers. For now, only 'clash'.
-		if parser.Type() == "clash" && parser.Support(processedContent) {
-			return parser.Parse(ctx, processedContent)
+	var bestParser WholeFileParser
+	bestConfidence := ConfidenceNone
+	for _, wfp := range m.wholeFileParsers {
+		confidence, err := wfp.Detect(processedContent)
+		if err != nil || confidence <= ConfidenceNone {
+			continue
 		}
+		if confidence > bestConfidence {
+			bestConfidence = confidence
+			bestParser = wfp
+		}
+	}
+
+	if bestParser != nil {
+		start := time.Now()
+		proxies, err := bestParser.Parse(ctx, processedContent)
+		duration := time.Since(start)
+		m.recordAttempt(ctx, bestParser.Type(), "", duration, err)
+		if err != nil {
+			diag := &LineError{LineNumber: 0, RawLine: "", ParserType: string(bestParser.Type()), Err: err}
+			return nil, []*LineError{diag}, &MultiError{Errors: []*LineError{diag}}
+		}
+		return proxies, nil, nil
 	}
 
 	// Stage 2: If no whole-file parser matched, assume it's a list of proxy links (one per line).
 	var allProxies []*proxy.Proxy
+	var diagnostics []*LineError
 	lines := splitContent(processedContent)
-	for _, line := range lines {
-		line = cleanLine(line)
+
+	for i, rawLine := range lines {
+		line := cleanLine(rawLine)
 		if line == "" {
 			continue
 		}
 
-		// Find a suitable line-based parser.
+		matched := false
 		for _, parser := range m.parsers {
-			if parser.Type() == "clash" { // Skip whole-file parsers here.
+			if !parser.Support(line) {
 				continue
 			}
-			if parser.Support(line) {
-				proxies, err := parser.Parse(ctx, line)
-				if err != nil {
-					m.logger.WithError(err).WithField("line", line).Warn("Failed to parse proxy line")
-					break // A parser supported the line but failed to parse it. Move to the next line.
+
+			matched = true
+			start := time.Now()
+			proxies, err := parser.Parse(ctx, line)
+			duration := time.Since(start)
+			m.recordAttempt(ctx, parser.Type(), rawLine, duration, err)
+			if err != nil {
+				m.logger.WithError(err).WithField("line", line).Warn("Failed to parse proxy line")
+				if m.options.Strict || m.options.CollectDiagnostics {
+					diagnostics = appendDiagnostic(diagnostics, m.options.MaxErrors, &LineError{
+						LineNumber: i + 1,
+						RawLine:    rawLine,
+						ParserType: string(parser.Type()),
+						Err:        err,
+					})
 				}
-				allProxies = append(allProxies, proxies...)
-				break // Successfully parsed the line. Move to the next line.
+				break // A parser supported the line but failed to parse it. Move to the next line.
 			}
+			allProxies = append(allProxies, proxies...)
+			break // Successfully parsed the line. Move to the next line.
 		}
+
+		if !matched && (m.options.Strict || m.options.CollectDiagnostics) {
+			diagnostics = appendDiagnostic(diagnostics, m.options.MaxErrors, &LineError{
+				LineNumber: i + 1,
+				RawLine:    rawLine,
+				ParserType: "unknown",
+				Err:        fmt.Errorf("no parser recognizes this line"),
+			})
+		}
+	}
+
+	if m.options.Strict && len(diagnostics) > 0 {
+		return nil, diagnostics, &MultiError{Errors: diagnostics}
 	}
 
-	return allProxies, nil
+	return allProxies, diagnostics, nil
 }
 
-// AddParser adds a custom parser to the manager
-func (m *Manager) AddParser(parser Parser) {
+// recordAttempt emits Prometheus metrics for a single parser.Parse call and,
+// if configured, forwards it to the tracing hook.
+func (m *Manager) recordAttempt(ctx context.Context, parserType proxy.Type, rawLine string, duration time.Duration, err error) {
+	middleware.ParserMetrics(string(parserType), duration, err == nil)
+	if m.hook != nil {
+		m.hook(ctx, parserType, rawLine, duration, err)
+	}
+}
+
+func appendDiagnostic(diagnostics []*LineError, maxErrors int, diag *LineError) []*LineError {
+	if maxErrors > 0 && len(diagnostics) >= maxErrors {
+		return diagnostics
+	}
+	return append(diagnostics, diag)
+}
+
+// AddParser adds a custom line parser to the manager
+func (m *Manager) AddParser(parser LineParser) {
 	m.parsers = append(m.parsers, parser)
 }
 
-// GetParsers returns all registered parsers
-func (m *Manager) GetParsers() []Parser {
+// AddWholeFileParser adds a custom whole-file parser to the manager
+func (m *Manager) AddWholeFileParser(parser WholeFileParser) {
+	m.wholeFileParsers = append(m.wholeFileParsers, parser)
+}
+
+// AddDecoder appends a custom content decoder to the decode chain
+func (m *Manager) AddDecoder(decoder ContentDecoder) {
+	m.decoders = append(m.decoders, decoder)
+}
+
+// GetParsers returns all registered line parsers
+func (m *Manager) GetParsers() []LineParser {
 	return m.parsers
 }
 
@@ -97,7 +97,7 @@ trojan://password@example.com:443#Trojan-Valid
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			proxies, err := manager.Parse(ctx, tt.content)
+			proxies, _, err := manager.Parse(ctx, tt.content)
 
 			require.NoError(t, err)
 			assert.Len(t, proxies, tt.expectedCount)
@@ -110,3 +110,38 @@ trojan://password@example.com:443#Trojan-Valid
 		})
 	}
 }
+
+func TestManager_Parse_Strict(t *testing.T) {
+	log := logger.New(logger.Config{Level: "panic"})
+	ctx := context.Background()
+
+	mixedContent := `
+ss://YWVzLTI1Ni1jZmI6cGFzc3dvcmQ@example.com:8388#SS-Valid
+this-is-an-invalid-line
+`
+
+	t.Run("strict mode rejects bad lines", func(t *testing.T) {
+		manager := NewManagerWithOptions(log, ParseOptions{Strict: true})
+
+		proxies, diagnostics, err := manager.Parse(ctx, mixedContent)
+
+		require.Error(t, err)
+		assert.Nil(t, proxies)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, 3, diagnostics[0].LineNumber)
+
+		var multiErr *MultiError
+		require.ErrorAs(t, err, &multiErr)
+	})
+
+	t.Run("non-strict mode collects diagnostics without failing", func(t *testing.T) {
+		manager := NewManagerWithOptions(log, ParseOptions{CollectDiagnostics: true})
+
+		proxies, diagnostics, err := manager.Parse(ctx, mixedContent)
+
+		require.NoError(t, err)
+		assert.Len(t, proxies, 1)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, "unknown", diagnostics[0].ParserType)
+	})
+}
@@ -11,38 +11,8 @@ import (
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
 )
 
-// VLESSParser parses VLESS protocol URLs
-type VLESSParser struct{}
-
-func NewVLESSParser() *VLESSParser { return &VLESSParser{} }
-func (p *VLESSParser) Type() proxy.Type { return proxy.Type("vless") }
-func (p *VLESSParser) Support(content string) bool { return strings.HasPrefix(content, "vless://") }
-func (p *VLESSParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
-	if !p.Support(content) {
-		return nil, fmt.Errorf("invalid vless URL format")
-	}
-	u, err := url.Parse(content)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse vless URL: %v", err)
-	}
-	port, _ := strconv.Atoi(u.Port())
-	name := u.Fragment
-	if name == "" {
-		name = fmt.Sprintf("VLESS-%s", u.Hostname())
-	}
-	result := &proxy.Proxy{
-		ID: uuid.New().String(),
-		Type: proxy.Type("vless"),
-		Name: name,
-		Server: u.Hostname(),
-		Port: port,
-		UUID: u.User.Username(),
-		UDP: true,
-	}
-	return []*proxy.Proxy{result}, nil
-}
-
-// HysteriaParser parses Hysteria protocol URLs
+// HysteriaParser parses Hysteria (v1) protocol URLs of the form
+// hysteria://host:port?auth=...&protocol=udp&peer=sni&insecure=1&upmbps=...&downmbps=...&obfs=...&alpn=...&mport=...#name
 type HysteriaParser struct{}
 
 func NewHysteriaParser() *HysteriaParser { return &HysteriaParser{} }
@@ -56,51 +26,44 @@ func (p *HysteriaParser) Parse(ctx context.Context, content string) ([]*proxy.Pr
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse hysteria URL: %v", err)
 	}
+
 	port, _ := strconv.Atoi(u.Port())
 	name := u.Fragment
 	if name == "" {
 		name = fmt.Sprintf("Hysteria-%s", u.Hostname())
 	}
-	result := &proxy.Proxy{
-		ID: uuid.New().String(),
-		Type: proxy.Type("hysteria"),
-		Name: name,
-		Server: u.Hostname(),
-		Port: port,
-		Password: u.User.Username(),
-		UDP: true,
-	}
-	return []*proxy.Proxy{result}, nil
-}
 
-// Hysteria2Parser parses Hysteria2 protocol URLs
-type Hysteria2Parser struct{}
+	query := u.Query()
 
-func NewHysteria2Parser() *Hysteria2Parser { return &Hysteria2Parser{} }
-func (p *Hysteria2Parser) Type() proxy.Type { return proxy.Type("hysteria2") }
-func (p *Hysteria2Parser) Support(content string) bool { return strings.HasPrefix(content, "hysteria2://") }
-func (p *Hysteria2Parser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
-	if !p.Support(content) {
-		return nil, fmt.Errorf("invalid hysteria2 URL format")
+	auth := query.Get("auth")
+	if auth == "" {
+		auth = u.User.Username()
 	}
-	u, err := url.Parse(content)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse hysteria2 URL: %v", err)
+
+	result := &proxy.Proxy{
+		ID:       uuid.New().String(),
+		Type:     proxy.Type("hysteria"),
+		Name:     name,
+		Server:   u.Hostname(),
+		Port:     port,
+		Password: auth,
+		Protocol: query.Get("protocol"),
+		SNI:      query.Get("peer"),
+		Obfs:     query.Get("obfs"),
+		UpMbps:   parseHysteriaBandwidth(query.Get("upmbps")),
+		DownMbps: parseHysteriaBandwidth(query.Get("downmbps")),
+		Ports:    query.Get("mport"),
+		UDP:      true,
 	}
-	port, _ := strconv.Atoi(u.Port())
-	name := u.Fragment
-	if name == "" {
-		name = fmt.Sprintf("Hysteria2-%s", u.Hostname())
+
+	if alpn := query.Get("alpn"); alpn != "" {
+		result.Alpn = strings.Split(alpn, ",")
 	}
-	result := &proxy.Proxy{
-		ID: uuid.New().String(),
-		Type: proxy.Type("hysteria2"),
-		Name: name,
-		Server: u.Hostname(),
-		Port: port,
-		Password: u.User.Username(),
-		UDP: true,
+
+	if insecure := query.Get("insecure"); insecure == "1" || strings.EqualFold(insecure, "true") {
+		result.SkipCertVerify = true
 	}
+
 	return []*proxy.Proxy{result}, nil
 }
 
@@ -180,6 +143,46 @@ func (p *HTTPParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy,
 	return []*proxy.Proxy{result}, nil
 }
 
+// SSHParser parses SSH tunnel protocol URLs
+type SSHParser struct{}
+
+func NewSSHParser() *SSHParser { return &SSHParser{} }
+func (p *SSHParser) Type() proxy.Type { return proxy.TypeSSH }
+func (p *SSHParser) Support(content string) bool { return strings.HasPrefix(content, "ssh://") }
+func (p *SSHParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
+	if !p.Support(content) {
+		return nil, fmt.Errorf("invalid ssh URL format")
+	}
+	u, err := url.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh URL: %v", err)
+	}
+	port, _ := strconv.Atoi(u.Port())
+	if port == 0 {
+		port = 22
+	}
+	name := u.Fragment
+	if name == "" {
+		name = fmt.Sprintf("SSH-%s", u.Hostname())
+	}
+	result := &proxy.Proxy{
+		ID: uuid.New().String(),
+		Type: proxy.TypeSSH,
+		Name: name,
+		Server: u.Hostname(),
+		Port: port,
+		Username: u.User.Username(),
+	}
+	if u.User != nil {
+		result.Password, _ = u.User.Password()
+	}
+	if privateKey := u.Query().Get("private-key"); privateKey != "" {
+		result.PrivateKey = privateKey
+		result.PrivateKeyPassphrase = u.Query().Get("private-key-passphrase")
+	}
+	return []*proxy.Proxy{result}, nil
+}
+
 // Socks5Parser parses SOCKS5 protocol URLs
 type Socks5Parser struct{}
 
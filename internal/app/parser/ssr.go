@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
 )
 
 type SSRParser struct{}
@@ -27,38 +28,38 @@ func (p *SSRParser) Support(content string) bool {
 
 func (p *SSRParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
 	if !p.Support(content) {
-		return nil, fmt.Errorf("invalid shadowsocksr URL format")
+		return nil, errors.ErrParseFailed.WithDetails("reason", "invalid shadowsocksr URL format")
 	}
 
 	// Remove the ssr:// prefix
 	content = strings.TrimPrefix(content, "ssr://")
-	
+
 	// Decode base64
 	decoded, err := base64.RawURLEncoding.DecodeString(content)
 	if err != nil {
 		// Try standard base64
 		decoded, err = base64.StdEncoding.DecodeString(content)
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode base64: %v", err)
+			return nil, errors.ErrParseFailed.WithCause(err).WithDetails("stage", "base64")
 		}
 	}
 
 	// Parse SSR format: server:port:protocol:method:obfs:password_base64/?params_base64
 	parts := strings.SplitN(string(decoded), "/?", 2)
 	if len(parts) == 0 {
-		return nil, fmt.Errorf("invalid SSR format")
+		return nil, errors.ErrParseFailed.WithDetails("reason", "invalid SSR format")
 	}
 
 	// Parse basic info
 	basicParts := strings.Split(parts[0], ":")
 	if len(basicParts) != 6 {
-		return nil, fmt.Errorf("invalid SSR basic format")
+		return nil, errors.ErrParseFailed.WithDetails("reason", "invalid SSR basic format")
 	}
 
 	server := basicParts[0]
 	port, err := strconv.Atoi(basicParts[1])
 	if err != nil {
-		return nil, fmt.Errorf("invalid port: %v", err)
+		return nil, errors.ErrParseFailed.WithCause(err).WithDetails("stage", "port")
 	}
 
 	protocol := basicParts[2]
@@ -70,7 +71,7 @@ func (p *SSRParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy,
 	if err != nil {
 		passwordDecoded, err = base64.StdEncoding.DecodeString(basicParts[5])
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode password: %v", err)
+			return nil, errors.ErrParseFailed.WithCause(err).WithDetails("stage", "password")
 		}
 	}
 	password := string(passwordDecoded)
@@ -84,14 +85,14 @@ func (p *SSRParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy,
 			if len(kv) == 2 {
 				key := kv[0]
 				value := kv[1]
-				
+
 				// Decode parameter values
 				if decoded, err := base64.RawURLEncoding.DecodeString(value); err == nil {
 					value = string(decoded)
 				} else if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
 					value = string(decoded)
 				}
-				
+
 				params[key] = value
 			}
 		}
@@ -125,4 +126,4 @@ func (p *SSRParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy,
 	}
 
 	return []*proxy.Proxy{result}, nil
-}
\ No newline at end of file
+}
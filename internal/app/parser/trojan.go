@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
 )
 
 type TrojanParser struct{}
@@ -27,17 +28,17 @@ func (p *TrojanParser) Support(content string) bool {
 
 func (p *TrojanParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
 	if !p.Support(content) {
-		return nil, fmt.Errorf("invalid trojan URL format")
+		return nil, fmt.Errorf("%w: invalid trojan URL format", errors.ErrParseFailed)
 	}
 
 	u, err := url.Parse(content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse trojan URL: %v", err)
+		return nil, fmt.Errorf("%w: failed to parse trojan URL: %v", errors.ErrParseFailed, err)
 	}
 
 	port, err := strconv.Atoi(u.Port())
 	if err != nil {
-		return nil, fmt.Errorf("invalid port: %v", err)
+		return nil, fmt.Errorf("%w: invalid port: %v", errors.ErrParseFailed, err)
 	}
 
 	name := u.Fragment
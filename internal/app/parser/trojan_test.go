@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+)
+
+func TestTrojanParser_Parse(t *testing.T) {
+	parser := NewTrojanParser()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected *proxy.Proxy
+		wantErr  bool
+	}{
+		{
+			name:  "tcp with sni and alpn",
+			input: "trojan://secret@example.com:443?sni=example.com&alpn=h2,http%2F1.1#Trojan-Node",
+			expected: &proxy.Proxy{
+				Server:   "example.com",
+				Port:     443,
+				Password: "secret",
+				TLS:      proxy.TLSRequire,
+				SNI:      "example.com",
+				Alpn:     []string{"h2", "http/1.1"},
+				Name:     "Trojan-Node",
+			},
+		},
+		{
+			name:  "websocket transport",
+			input: "trojan://secret@example.com:443?type=ws&host=cdn.example.com&path=%2Fws#WS-Node",
+			expected: &proxy.Proxy{
+				Server:   "example.com",
+				Port:     443,
+				Password: "secret",
+				TLS:      proxy.TLSRequire,
+				Host:     "cdn.example.com",
+				Path:     "/ws",
+				Name:     "WS-Node",
+			},
+		},
+		{
+			name:  "grpc transport uses serviceName as path",
+			input: "trojan://secret@example.com:443?type=grpc&serviceName=grpc-service#GRPC-Node",
+			expected: &proxy.Proxy{
+				Server:   "example.com",
+				Port:     443,
+				Password: "secret",
+				TLS:      proxy.TLSRequire,
+				Path:     "grpc-service",
+				Name:     "GRPC-Node",
+			},
+		},
+		{
+			name:  "percent-encoded remark",
+			input: "trojan://secret@example.com:443#%E9%A6%99%E6%B8%AF%2001",
+			expected: &proxy.Proxy{
+				Server:   "example.com",
+				Port:     443,
+				Password: "secret",
+				TLS:      proxy.TLSRequire,
+				Name:     "香港 01",
+			},
+		},
+		{
+			name:  "name defaults to server when fragment is missing",
+			input: "trojan://secret@example.com:443",
+			expected: &proxy.Proxy{
+				Server:   "example.com",
+				Port:     443,
+				Password: "secret",
+				TLS:      proxy.TLSRequire,
+				Name:     "Trojan-example.com",
+			},
+		},
+		{
+			name:    "invalid scheme",
+			input:   "invalid://test",
+			wantErr: true,
+		},
+		{
+			name:    "missing port",
+			input:   "trojan://secret@example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(ctx, tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+
+			p := result[0]
+			assert.Equal(t, tt.expected.Server, p.Server)
+			assert.Equal(t, tt.expected.Port, p.Port)
+			assert.Equal(t, tt.expected.Password, p.Password)
+			assert.Equal(t, tt.expected.TLS, p.TLS)
+			assert.Equal(t, tt.expected.SNI, p.SNI)
+			assert.Equal(t, tt.expected.Alpn, p.Alpn)
+			assert.Equal(t, tt.expected.Host, p.Host)
+			assert.Equal(t, tt.expected.Path, p.Path)
+			assert.Equal(t, tt.expected.Name, p.Name)
+		})
+	}
+}
+
+func TestTrojanParser_Support(t *testing.T) {
+	parser := NewTrojanParser()
+
+	assert.True(t, parser.Support("trojan://test"))
+	assert.False(t, parser.Support("vmess://test"))
+	assert.False(t, parser.Support("invalid://test"))
+}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+)
+
+// VLESSParser parses VLESS protocol URLs
+type VLESSParser struct{}
+
+func NewVLESSParser() *VLESSParser { return &VLESSParser{} }
+func (p *VLESSParser) Type() proxy.Type { return proxy.Type("vless") }
+func (p *VLESSParser) Support(content string) bool { return strings.HasPrefix(content, "vless://") }
+
+func (p *VLESSParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
+	if !p.Support(content) {
+		return nil, fmt.Errorf("%w: invalid vless URL format", errors.ErrParseFailed)
+	}
+	u, err := url.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse vless URL: %v", errors.ErrParseFailed, err)
+	}
+	port, _ := strconv.Atoi(u.Port())
+	name := u.Fragment
+	if name == "" {
+		name = fmt.Sprintf("VLESS-%s", u.Hostname())
+	}
+	query := u.Query()
+
+	var alpn []string
+	if alpnStr := query.Get("alpn"); alpnStr != "" {
+		alpn = strings.Split(alpnStr, ",")
+	}
+
+	result := &proxy.Proxy{
+		ID:         uuid.New().String(),
+		Type:       proxy.Type("vless"),
+		Name:       name,
+		Server:     u.Hostname(),
+		Port:       port,
+		UUID:       u.User.Username(),
+		UDP:        true,
+		Flow:       query.Get("flow"),
+		Method:     query.Get("encryption"),
+		Security:   query.Get("security"),
+		Network:    proxy.Network(query.Get("type")),
+		Host:       query.Get("host"),
+		SNI:        query.Get("sni"),
+		Alpn:       alpn,
+		HeaderType: query.Get("headerType"),
+	}
+
+	switch query.Get("security") {
+	case "tls":
+		result.TLS = proxy.TLSRequire
+		result.Fingerprint = query.Get("fp")
+	case "reality":
+		result.TLS = proxy.TLSRequire
+		result.Fingerprint = query.Get("fp")
+		result.PublicKey = query.Get("pbk")
+		result.ShortID = query.Get("sid")
+		result.SpiderX = query.Get("spx")
+	}
+
+	switch query.Get("type") {
+	case "grpc":
+		result.ServiceName = query.Get("serviceName")
+		result.GRPCMode = query.Get("mode")
+	case "ws", "h2":
+		result.Path = query.Get("path")
+		if result.Host == "" {
+			result.Host = query.Get("host")
+		}
+	default:
+		result.Path = query.Get("path")
+	}
+
+	return []*proxy.Proxy{result}, nil
+}
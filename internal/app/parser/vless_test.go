@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVLESSParser_Parse(t *testing.T) {
+	parser := NewVLESSParser()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected *proxy.Proxy
+		wantErr  bool
+	}{
+		{
+			name:  "reality with tcp transport",
+			input: "vless://uuid-1@example.com:443?security=reality&flow=xtls-rprx-vision&fp=chrome&pbk=pubkey&sid=shortid&spx=%2F&type=tcp&headerType=none#Reality-Node",
+			expected: &proxy.Proxy{
+				Server:      "example.com",
+				Port:        443,
+				UUID:        "uuid-1",
+				Security:    "reality",
+				Flow:        "xtls-rprx-vision",
+				TLS:         proxy.TLSRequire,
+				Fingerprint: "chrome",
+				PublicKey:   "pubkey",
+				ShortID:     "shortid",
+				SpiderX:     "/",
+				Network:     proxy.Network("tcp"),
+				HeaderType:  "none",
+				Name:        "Reality-Node",
+			},
+		},
+		{
+			name:  "tls with websocket transport",
+			input: "vless://uuid-2@example.com:443?security=tls&fp=chrome&sni=example.com&alpn=h2,http%2F1.1&type=ws&path=%2Fws&host=cdn.example.com#WS-Node",
+			expected: &proxy.Proxy{
+				Server:      "example.com",
+				Port:        443,
+				UUID:        "uuid-2",
+				Security:    "tls",
+				TLS:         proxy.TLSRequire,
+				Fingerprint: "chrome",
+				SNI:         "example.com",
+				Alpn:        []string{"h2", "http/1.1"},
+				Network:     proxy.Network("ws"),
+				Path:        "/ws",
+				Host:        "cdn.example.com",
+				Name:        "WS-Node",
+			},
+		},
+		{
+			name:  "grpc transport",
+			input: "vless://uuid-3@example.com:443?security=tls&type=grpc&serviceName=grpc-service&mode=gun#GRPC-Node",
+			expected: &proxy.Proxy{
+				Server:      "example.com",
+				Port:        443,
+				UUID:        "uuid-3",
+				Security:    "tls",
+				TLS:         proxy.TLSRequire,
+				Network:     proxy.Network("grpc"),
+				ServiceName: "grpc-service",
+				GRPCMode:    "gun",
+				Name:        "GRPC-Node",
+			},
+		},
+		{
+			name:  "name defaults to server when fragment is missing",
+			input: "vless://uuid-4@example.com:443",
+			expected: &proxy.Proxy{
+				Server: "example.com",
+				Port:   443,
+				UUID:   "uuid-4",
+				Name:   "VLESS-example.com",
+			},
+		},
+		{
+			name:    "invalid format",
+			input:   "invalid://test",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(ctx, tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+
+			p := result[0]
+			assert.Equal(t, tt.expected.Server, p.Server)
+			assert.Equal(t, tt.expected.Port, p.Port)
+			assert.Equal(t, tt.expected.UUID, p.UUID)
+			assert.Equal(t, tt.expected.Security, p.Security)
+			assert.Equal(t, tt.expected.Flow, p.Flow)
+			assert.Equal(t, tt.expected.TLS, p.TLS)
+			assert.Equal(t, tt.expected.Fingerprint, p.Fingerprint)
+			assert.Equal(t, tt.expected.PublicKey, p.PublicKey)
+			assert.Equal(t, tt.expected.ShortID, p.ShortID)
+			assert.Equal(t, tt.expected.SpiderX, p.SpiderX)
+			assert.Equal(t, tt.expected.Network, p.Network)
+			assert.Equal(t, tt.expected.HeaderType, p.HeaderType)
+			assert.Equal(t, tt.expected.SNI, p.SNI)
+			assert.Equal(t, tt.expected.Alpn, p.Alpn)
+			assert.Equal(t, tt.expected.Path, p.Path)
+			assert.Equal(t, tt.expected.Host, p.Host)
+			assert.Equal(t, tt.expected.ServiceName, p.ServiceName)
+			assert.Equal(t, tt.expected.GRPCMode, p.GRPCMode)
+			assert.Equal(t, tt.expected.Name, p.Name)
+		})
+	}
+}
+
+func TestVLESSParser_Support(t *testing.T) {
+	parser := NewVLESSParser()
+
+	assert.True(t, parser.Support("vless://test"))
+	assert.False(t, parser.Support("vmess://test"))
+	assert.False(t, parser.Support("invalid://test"))
+}
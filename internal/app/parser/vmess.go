@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
 )
 
 type VMessParser struct{}
@@ -28,19 +29,19 @@ func (p *VMessParser) Support(content string) bool {
 
 func (p *VMessParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy, error) {
 	if !p.Support(content) {
-		return nil, fmt.Errorf("invalid vmess URL format")
+		return nil, fmt.Errorf("%w: invalid vmess URL format", errors.ErrParseFailed)
 	}
 
 	// Remove the vmess:// prefix
 	content = strings.TrimPrefix(content, "vmess://")
-	
+
 	// Decode base64
 	decoded, err := base64.RawURLEncoding.DecodeString(content)
 	if err != nil {
 		// Try standard base64
 		decoded, err = base64.StdEncoding.DecodeString(content)
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode base64: %v", err)
+			return nil, fmt.Errorf("%w: failed to decode base64: %v", errors.ErrParseFailed, err)
 		}
 	}
 
@@ -63,12 +64,12 @@ func (p *VMessParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy
 	}
 
 	if err := json.Unmarshal(decoded, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse vmess config: %v", err)
+		return nil, fmt.Errorf("%w: failed to parse vmess config: %v", errors.ErrParseFailed, err)
 	}
 
 	port, err := strconv.Atoi(config.Port)
 	if err != nil {
-		return nil, fmt.Errorf("invalid port: %v", err)
+		return nil, fmt.Errorf("%w: invalid port: %v", errors.ErrParseFailed, err)
 	}
 
 	aid, err := strconv.Atoi(config.AID)
@@ -81,17 +82,13 @@ func (p *VMessParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy
 		name = fmt.Sprintf("VMess-%s", config.Add)
 	}
 
-	// Parse network
+	// Parse network. config.Net carries the transport, not just tcp/udp -
+	// "ws", "grpc", "h2", and "quic" are the common v2rayN values and, like
+	// VLESSParser.Parse, are kept verbatim so the generator can tell them
+	// apart (e.g. clash.go's ws-opts/grpc-opts selection).
 	network := proxy.NetworkTCP
-	if config.Net != "" {
-		switch strings.ToLower(config.Net) {
-		case "tcp":
-			network = proxy.NetworkTCP
-		case "udp":
-			network = proxy.NetworkUDP
-		case "tcp,udp":
-			network = proxy.NetworkTCPUDP
-		}
+	if net := strings.ToLower(config.Net); net != "" {
+		network = proxy.Network(net)
 	}
 
 	// Parse TLS
@@ -113,22 +110,24 @@ func (p *VMessParser) Parse(ctx context.Context, content string) ([]*proxy.Proxy
 	}
 
 	result := &proxy.Proxy{
-		ID:       uuid.New().String(),
-		Type:     proxy.Type("vmess"),
-		Name:     name,
-		Server:   config.Add,
-		Port:     port,
-		UUID:     config.ID,
-		AID:      aid,
-		Method:   config.Scy,
-		Network:  network,
-		TLS:      tls,
-		SNI:      config.SNI,
-		Host:     config.Host,
-		Path:     config.Path,
-		Headers:  headers,
-		Alpn:     alpn,
-		UDP:      network == proxy.NetworkUDP || network == proxy.NetworkTCPUDP,
+		ID:          uuid.New().String(),
+		Type:        proxy.Type("vmess"),
+		Name:        name,
+		Server:      config.Add,
+		Port:        port,
+		UUID:        config.ID,
+		AID:         aid,
+		Method:      config.Scy,
+		Network:     network,
+		TLS:         tls,
+		SNI:         config.SNI,
+		Host:        config.Host,
+		Path:        config.Path,
+		Headers:     headers,
+		Alpn:        alpn,
+		UDP:         network == proxy.NetworkUDP || network == proxy.NetworkTCPUDP,
+		Fingerprint: config.FP,
+		HeaderType:  config.Type,
 	}
 
 	return []*proxy.Proxy{result}, nil
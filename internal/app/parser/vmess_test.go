@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/subconverter/subconverter-go/internal/domain/proxy"
+)
+
+// validVMessURI is a v2rayN-format vmess:// link encoding:
+//
+//	{"v":"2","ps":"VMess-Node","add":"example.com","port":"443","id":"b831381d-6324-4d53-ad4f-8cda48b30811",
+//	 "aid":"0","scy":"auto","net":"ws","type":"none","host":"cdn.example.com","path":"/vmess",
+//	 "tls":"tls","sni":"example.com","alpn":"h2,http/1.1"}
+const validVMessURI = "vmess://eyJ2IjogIjIiLCAicHMiOiAiVk1lc3MtTm9kZSIsICJhZGQiOiAiZXhhbXBsZS5jb20iLCAicG9ydCI6ICI0NDMiLCAiaWQiOiAiYjgzMTM4MWQtNjMyNC00ZDUzLWFkNGYtOGNkYTQ4YjMwODExIiwgImFpZCI6ICIwIiwgInNjeSI6ICJhdXRvIiwgIm5ldCI6ICJ3cyIsICJ0eXBlIjogIm5vbmUiLCAiaG9zdCI6ICJjZG4uZXhhbXBsZS5jb20iLCAicGF0aCI6ICIvdm1lc3MiLCAidGxzIjogInRscyIsICJzbmkiOiAiZXhhbXBsZS5jb20iLCAiYWxwbiI6ICJoMixodHRwLzEuMSJ9"
+
+// vmessURIWithUnicodeRemark is the same config with a non-ASCII "ps"
+// remark ("香港 01") - vmess encodes the remark inside the base64 JSON
+// payload rather than as a URL fragment, so there is no percent-encoding
+// involved on the wire, just JSON/UTF-8 in the decoded body.
+const vmessURIWithUnicodeRemark = "vmess://eyJ2IjogIjIiLCAicHMiOiAi6aaZ5rivIDAxIiwgImFkZCI6ICJleGFtcGxlLmNvbSIsICJwb3J0IjogIjQ0MyIsICJpZCI6ICJiODMxMzgxZC02MzI0LTRkNTMtYWQ0Zi04Y2RhNDhiMzA4MTEiLCAiYWlkIjogIjAiLCAic2N5IjogImF1dG8iLCAibmV0IjogIndzIiwgInR5cGUiOiAibm9uZSIsICJob3N0IjogImNkbi5leGFtcGxlLmNvbSIsICJwYXRoIjogIi92bWVzcyIsICJ0bHMiOiAidGxzIiwgInNuaSI6ICJleGFtcGxlLmNvbSIsICJhbHBuIjogImgyLGh0dHAvMS4xIn0="
+
+func TestVMessParser_Parse(t *testing.T) {
+	parser := NewVMessParser()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected *proxy.Proxy
+		wantErr  bool
+	}{
+		{
+			name:  "websocket with tls",
+			input: validVMessURI,
+			expected: &proxy.Proxy{
+				Name:       "VMess-Node",
+				Server:     "example.com",
+				Port:       443,
+				UUID:       "b831381d-6324-4d53-ad4f-8cda48b30811",
+				AID:        0,
+				Method:     "auto",
+				Network:    proxy.Network("ws"),
+				TLS:        proxy.TLSRequire,
+				SNI:        "example.com",
+				Host:       "cdn.example.com",
+				Path:       "/vmess",
+				Alpn:       []string{"h2", "http/1.1"},
+				HeaderType: "none",
+			},
+		},
+		{
+			name:  "unicode remark survives JSON decoding",
+			input: vmessURIWithUnicodeRemark,
+			expected: &proxy.Proxy{
+				Name: "香港 01",
+			},
+		},
+		{
+			name:    "invalid scheme",
+			input:   "invalid://test",
+			wantErr: true,
+		},
+		{
+			name:    "malformed base64",
+			input:   "vmess://not-valid-base64!!!",
+			wantErr: true,
+		},
+		{
+			name:    "valid base64 but not JSON",
+			input:   "vmess://bm90IGpzb24=",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(ctx, tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+
+			p := result[0]
+			assert.Equal(t, tt.expected.Name, p.Name)
+			if tt.expected.Server != "" {
+				assert.Equal(t, tt.expected.Server, p.Server)
+				assert.Equal(t, tt.expected.Port, p.Port)
+				assert.Equal(t, tt.expected.UUID, p.UUID)
+				assert.Equal(t, tt.expected.AID, p.AID)
+				assert.Equal(t, tt.expected.Method, p.Method)
+				assert.Equal(t, tt.expected.Network, p.Network)
+				assert.Equal(t, tt.expected.TLS, p.TLS)
+				assert.Equal(t, tt.expected.SNI, p.SNI)
+				assert.Equal(t, tt.expected.Host, p.Host)
+				assert.Equal(t, tt.expected.Path, p.Path)
+				assert.Equal(t, tt.expected.Alpn, p.Alpn)
+				assert.Equal(t, tt.expected.HeaderType, p.HeaderType)
+				assert.Equal(t, tt.expected.Fingerprint, p.Fingerprint)
+			}
+		})
+	}
+}
+
+func TestVMessParser_Support(t *testing.T) {
+	parser := NewVMessParser()
+
+	assert.True(t, parser.Support("vmess://test"))
+	assert.False(t, parser.Support("trojan://test"))
+	assert.False(t, parser.Support("invalid://test"))
+}
@@ -0,0 +1,119 @@
+// Package ruleprovider fetches and caches the remote rule lists referenced
+// by ruleset.RuleSet.Provider, so Clash/Surge/sing-box clients that can't
+// fetch those lists themselves can pull them from our own
+// /api/v1/providers/:name endpoint instead.
+package ruleprovider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
+	"github.com/subconverter/subconverter-go/internal/infra/http"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+)
+
+// Store tracks named rule providers and keeps their bodies cached.
+type Store struct {
+	mu        sync.RWMutex
+	providers map[string]*ruleset.RuleProvider
+
+	client *http.Client
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NewStore creates a provider store that fetches with client and caches
+// bodies in cache for ttl, unless a provider specifies its own interval.
+func NewStore(client *http.Client, cache cache.Cache, ttl time.Duration, log *logger.Logger) *Store {
+	return &Store{
+		providers: make(map[string]*ruleset.RuleProvider),
+		client:    client,
+		cache:     cache,
+		ttl:       ttl,
+		logger:    log,
+	}
+}
+
+// Register records a named provider so it can be fetched via Get and kept
+// warm by StartBackgroundRefresh.
+func (s *Store) Register(name string, provider *ruleset.RuleProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providers[name] = provider
+}
+
+// Get returns name's cached body, fetching it on a cache miss.
+func (s *Store) Get(ctx context.Context, name string) ([]byte, error) {
+	s.mu.RLock()
+	provider, ok := s.providers[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, errors.NotFound("PROVIDER_NOT_FOUND", fmt.Sprintf("rule provider not found: %s", name))
+	}
+
+	cacheKey := cacheKeyFor(name)
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
+		return cached, nil
+	}
+
+	return s.refresh(ctx, name, provider)
+}
+
+func (s *Store) refresh(ctx context.Context, name string, provider *ruleset.RuleProvider) ([]byte, error) {
+	body, err := s.client.Get(ctx, provider.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to fetch rule provider: %s", name))
+	}
+
+	ttl := s.ttl
+	if provider.Interval > 0 {
+		ttl = time.Duration(provider.Interval) * time.Second
+	}
+	s.cache.Set(ctx, cacheKeyFor(name), body, ttl)
+
+	return body, nil
+}
+
+// StartBackgroundRefresh refreshes every registered provider once per
+// interval until ctx is cancelled, keeping the cache warm ahead of client
+// requests. It returns immediately; refreshing happens in a goroutine.
+func (s *Store) StartBackgroundRefresh(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.refreshAll(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Store) refreshAll(ctx context.Context) {
+	s.mu.RLock()
+	providers := make(map[string]*ruleset.RuleProvider, len(s.providers))
+	for name, provider := range s.providers {
+		providers[name] = provider
+	}
+	s.mu.RUnlock()
+
+	for name, provider := range providers {
+		if _, err := s.refresh(ctx, name, provider); err != nil {
+			s.logger.WithError(err).Warn("failed to refresh rule provider")
+		}
+	}
+}
+
+func cacheKeyFor(name string) string {
+	return "ruleprovider:" + name
+}
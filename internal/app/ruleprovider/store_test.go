@@ -0,0 +1,53 @@
+package ruleprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/domain/ruleset"
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
+	apphttp "github.com/subconverter/subconverter-go/internal/infra/http"
+	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore() *Store {
+	log := logger.New(logger.Config{Level: "error", Format: "text", Output: "stdout"})
+	client, err := apphttp.NewClient(apphttp.ProxyConfig{})
+	if err != nil {
+		panic(err)
+	}
+	return NewStore(client, cache.NewMemoryCache(), time.Minute, log)
+}
+
+func TestStore_GetUnregisteredReturnsNotFound(t *testing.T) {
+	store := newTestStore()
+
+	_, err := store.Get(context.Background(), "missing")
+	assert.Error(t, err)
+}
+
+func TestStore_GetFetchesAndCaches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("DOMAIN-SUFFIX,example.com"))
+	}))
+	defer server.Close()
+
+	store := newTestStore()
+	store.Register("ad-block", &ruleset.RuleProvider{
+		URL:      server.URL,
+		Behavior: ruleset.BehaviorDomain,
+	})
+
+	body, err := store.Get(context.Background(), "ad-block")
+	require.NoError(t, err)
+	assert.Equal(t, "DOMAIN-SUFFIX,example.com", string(body))
+
+	cached, err := store.cache.Get(context.Background(), cacheKeyFor("ad-block"))
+	require.NoError(t, err)
+	assert.Equal(t, body, cached)
+}
@@ -0,0 +1,111 @@
+// Package shortener persists opaque short-link tokens that resolve back
+// to an arbitrary JSON payload (a converter.ConvertRequest, in practice),
+// so callers can share a stable token instead of a giant query string.
+package shortener
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+)
+
+// record is the on-disk representation of a created short link.
+type record struct {
+	Payload        []byte    `json:"payload"`
+	PassphraseHash string    `json:"passphrase_hash,omitempty"`
+	Hits           int       `json:"hits"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Service creates and resolves short-link tokens, backed by an embedded
+// KV cache (converter.NewService opens a bolt store, falling back to an
+// in-memory cache) with a fixed TTL.
+type Service struct {
+	store cache.Cache
+	ttl   time.Duration
+}
+
+// NewService creates a shortener backed by store, whose entries expire
+// after ttl (0 means they never expire).
+func NewService(store cache.Cache, ttl time.Duration) *Service {
+	return &Service{store: store, ttl: ttl}
+}
+
+// Create persists payload under a new opaque token, optionally requiring
+// passphrase to resolve it, and returns the token.
+func (s *Service) Create(ctx context.Context, payload []byte, passphrase string) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate short-link token")
+	}
+
+	rec := record{Payload: payload, CreatedAt: time.Now()}
+	if passphrase != "" {
+		rec.PassphraseHash = hashPassphrase(passphrase)
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode short-link record")
+	}
+
+	if err := s.store.Set(ctx, cacheKeyFor(token), data, s.ttl); err != nil {
+		return "", errors.Wrap(err, "failed to persist short link")
+	}
+
+	return token, nil
+}
+
+// Resolve returns token's payload and bumps its hit counter. If the
+// record was created with a passphrase, passphrase must match it.
+func (s *Service) Resolve(ctx context.Context, token, passphrase string) ([]byte, error) {
+	raw, err := s.store.Get(ctx, cacheKeyFor(token))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to look up short link")
+	}
+	if len(raw) == 0 {
+		return nil, errors.NotFound("SHORT_LINK_NOT_FOUND", "short link not found or expired")
+	}
+
+	var rec record
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, errors.Wrap(err, "failed to decode short-link record")
+	}
+
+	if rec.PassphraseHash != "" && rec.PassphraseHash != hashPassphrase(passphrase) {
+		return nil, errors.Unauthorized("SHORT_LINK_PASSPHRASE_REQUIRED", "short link requires the correct passphrase")
+	}
+
+	rec.Hits++
+	if data, err := json.Marshal(rec); err == nil {
+		// Best-effort: a lost hit count isn't worth failing the resolve over.
+		_ = s.store.Set(ctx, cacheKeyFor(token), data, s.ttl)
+	}
+
+	return rec.Payload, nil
+}
+
+func cacheKeyFor(token string) string {
+	return "shortlink:" + token
+}
+
+func hashPassphrase(passphrase string) string {
+	sum := sha256.Sum256([]byte(passphrase))
+	return hex.EncodeToString(sum[:])
+}
+
+// newToken returns a random, URL-safe opaque token.
+func newToken() (string, error) {
+	buf := make([]byte, 9)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
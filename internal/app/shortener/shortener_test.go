@@ -0,0 +1,71 @@
+package shortener
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService() *Service {
+	return NewService(cache.NewMemoryCache(), time.Minute)
+}
+
+func TestService_CreateAndResolve(t *testing.T) {
+	svc := newTestService()
+	ctx := context.Background()
+
+	token, err := svc.Create(ctx, []byte(`{"target":"clash"}`), "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	payload, err := svc.Resolve(ctx, token, "")
+	require.NoError(t, err)
+	assert.Equal(t, `{"target":"clash"}`, string(payload))
+}
+
+func TestService_ResolveUnknownTokenReturnsNotFound(t *testing.T) {
+	svc := newTestService()
+	_, err := svc.Resolve(context.Background(), "does-not-exist", "")
+	assert.Error(t, err)
+}
+
+func TestService_PassphraseRequiredAndEnforced(t *testing.T) {
+	svc := newTestService()
+	ctx := context.Background()
+
+	token, err := svc.Create(ctx, []byte("payload"), "secret")
+	require.NoError(t, err)
+
+	_, err = svc.Resolve(ctx, token, "wrong")
+	assert.Error(t, err)
+
+	payload, err := svc.Resolve(ctx, token, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(payload))
+}
+
+func TestService_HitsIncrementOnResolve(t *testing.T) {
+	store := cache.NewMemoryCache()
+	svc := NewService(store, time.Minute)
+	ctx := context.Background()
+
+	token, err := svc.Create(ctx, []byte("payload"), "")
+	require.NoError(t, err)
+
+	_, err = svc.Resolve(ctx, token, "")
+	require.NoError(t, err)
+	_, err = svc.Resolve(ctx, token, "")
+	require.NoError(t, err)
+
+	raw, err := store.Get(ctx, cacheKeyFor(token))
+	require.NoError(t, err)
+
+	var rec record
+	require.NoError(t, json.Unmarshal(raw, &rec))
+	assert.Equal(t, 2, rec.Hits)
+}
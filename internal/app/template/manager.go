@@ -2,12 +2,17 @@ package template
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
 	"github.com/subconverter/subconverter-go/internal/pkg/errors"
 	"github.com/subconverter/subconverter-go/internal/pkg/logger"
 )
@@ -17,23 +22,31 @@ type Manager struct {
 	templatesDir string
 	rulesDir     string
 	logger       logger.Logger
-	cache        map[string]*template.Template
+	store        cache.Cache
+	ttl          time.Duration
 }
 
-// NewManager creates a new template manager
-func NewManager(templatesDir, rulesDir string, logger logger.Logger) *Manager {
+// NewManager creates a new template manager. store backs the persistent
+// cache of loaded templates, loaded rules and rendered subscriptions; ttl
+// controls how long cached entries are kept before being re-read from disk
+// or re-rendered.
+func NewManager(templatesDir, rulesDir string, store cache.Cache, ttl time.Duration, logger logger.Logger) *Manager {
 	return &Manager{
 		templatesDir: templatesDir,
 		rulesDir:     rulesDir,
 		logger:       logger,
-		cache:        make(map[string]*template.Template),
+		store:        store,
+		ttl:          ttl,
 	}
 }
 
 // LoadTemplate loads a template from file
 func (m *Manager) LoadTemplate(ctx context.Context, name string) (*template.Template, error) {
-	if tmpl, exists := m.cache[name]; exists {
-		return tmpl, nil
+	cacheKey := "template:" + name
+	if cached, err := m.store.Get(ctx, cacheKey); err == nil && cached != nil {
+		if tmpl, err := template.New(name).Parse(string(cached)); err == nil {
+			return tmpl, nil
+		}
 	}
 
 	filePath := filepath.Join(m.templatesDir, name)
@@ -51,12 +64,20 @@ func (m *Manager) LoadTemplate(ctx context.Context, name string) (*template.Temp
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to parse template %s", name))
 	}
 
-	m.cache[name] = tmpl
+	if err := m.store.Set(ctx, cacheKey, content, m.ttl); err != nil {
+		m.logger.WithError(err).Warn("failed to persist template cache entry")
+	}
+
 	return tmpl, nil
 }
 
 // RenderTemplate renders a template with data
 func (m *Manager) RenderTemplate(ctx context.Context, name string, data interface{}) (string, error) {
+	cacheKey := fmt.Sprintf("rendered:%s:%s", name, hashRenderData(data))
+	if cached, err := m.store.Get(ctx, cacheKey); err == nil && cached != nil {
+		return string(cached), nil
+	}
+
 	tmpl, err := m.LoadTemplate(ctx, name)
 	if err != nil {
 		return "", err
@@ -67,11 +88,21 @@ func (m *Manager) RenderTemplate(ctx context.Context, name string, data interfac
 		return "", errors.Wrap(err, fmt.Sprintf("failed to render template %s", name))
 	}
 
-	return builder.String(), nil
+	rendered := builder.String()
+	if err := m.store.Set(ctx, cacheKey, []byte(rendered), m.ttl); err != nil {
+		m.logger.WithError(err).Warn("failed to persist rendered template cache entry")
+	}
+
+	return rendered, nil
 }
 
 // LoadRule loads a rule file from disk
 func (m *Manager) LoadRule(ctx context.Context, rulePath string) ([]string, error) {
+	cacheKey := "rule:" + rulePath
+	if cached, err := m.store.Get(ctx, cacheKey); err == nil && cached != nil {
+		return parseRuleLines(string(cached)), nil
+	}
+
 	fullPath := filepath.Join(m.rulesDir, rulePath)
 
 	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
@@ -83,7 +114,15 @@ func (m *Manager) LoadRule(ctx context.Context, rulePath string) ([]string, erro
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to read rule %s", rulePath))
 	}
 
-	lines := strings.Split(string(content), "\n")
+	if err := m.store.Set(ctx, cacheKey, content, m.ttl); err != nil {
+		m.logger.WithError(err).Warn("failed to persist rule cache entry")
+	}
+
+	return parseRuleLines(string(content)), nil
+}
+
+func parseRuleLines(content string) []string {
+	lines := strings.Split(content, "\n")
 	var rules []string
 
 	for _, line := range lines {
@@ -93,7 +132,19 @@ func (m *Manager) LoadRule(ctx context.Context, rulePath string) ([]string, erro
 		}
 	}
 
-	return rules, nil
+	return rules
+}
+
+// hashRenderData produces a stable cache-key fragment for the data passed to
+// RenderTemplate, so identical inputs reuse a previously rendered output.
+func hashRenderData(data interface{}) string {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprintf("%v", data)
+	}
+
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
 }
 
 // ListTemplates lists all available templates
@@ -154,7 +205,15 @@ func (m *Manager) GetRulesPath() string {
 	return m.rulesDir
 }
 
-// ClearCache clears the template cache
+// ClearCache discards every cached template, rule and rendered subscription,
+// forcing the next load to hit disk again.
 func (m *Manager) ClearCache() {
-	m.cache = make(map[string]*template.Template)
+	flusher, ok := m.store.(cache.Flusher)
+	if !ok {
+		return
+	}
+
+	if err := flusher.Flush(context.Background()); err != nil {
+		m.logger.WithError(err).Warn("failed to flush template cache")
+	}
 }
@@ -0,0 +1,41 @@
+package dns
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+)
+
+// cachedAnswer is Answer's JSON-serializable form for storage in a
+// cache.Cache, the same way healthcheck.probeResult and fetcher.Result
+// round-trip through JSON.
+type cachedAnswer struct {
+	IP  string        `json:"ip"`
+	TTL time.Duration `json:"ttl"`
+}
+
+func encodeCachedAnswers(answers []Answer) []byte {
+	cached := make([]cachedAnswer, len(answers))
+	for i, a := range answers {
+		cached[i] = cachedAnswer{IP: a.IP.String(), TTL: a.TTL}
+	}
+	data, _ := json.Marshal(cached)
+	return data
+}
+
+func decodeCachedAnswers(data []byte) ([]Answer, error) {
+	var cached []cachedAnswer
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+
+	answers := make([]Answer, 0, len(cached))
+	for _, c := range cached {
+		ip := net.ParseIP(c.IP)
+		if ip == nil {
+			continue
+		}
+		answers = append(answers, Answer{IP: ip, TTL: c.TTL})
+	}
+	return answers, nil
+}
@@ -0,0 +1,36 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Chain tries each of its resolvers in order, returning the first
+// successful answer - the DoH -> system -> static fallback this
+// package's callers are expected to configure, so a DoH outage degrades
+// to the OS resolver instead of failing every fetch and proxy dial.
+type Chain struct {
+	resolvers []Resolver
+}
+
+// NewChain builds a Chain trying resolvers in the given order.
+func NewChain(resolvers ...Resolver) *Chain {
+	return &Chain{resolvers: resolvers}
+}
+
+// Resolve returns the first resolver's successful answer, or an
+// aggregate error if every resolver failed.
+func (c *Chain) Resolve(ctx context.Context, host string) ([]Answer, error) {
+	var errs []string
+	for _, resolver := range c.resolvers {
+		answers, err := resolver.Resolve(ctx, host)
+		if err == nil && len(answers) > 0 {
+			return answers, nil
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return nil, fmt.Errorf("dns: every resolver failed for %q: %s", host, strings.Join(errs, "; "))
+}
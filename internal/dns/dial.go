@@ -0,0 +1,40 @@
+package dns
+
+import (
+	"context"
+	"net"
+)
+
+// dialer is the subset of *net.Dialer that DialContext needs, so tests
+// can substitute a fake without opening a real socket.
+type dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// DialContext returns a dial function resolving addr's host through
+// resolver before handing the literal IP to base - the shape
+// http.Transport.DialContext and most dialers in this codebase expect.
+// addr whose host is already a literal IP is dialed as-is, unresolved.
+// On success, it dials the first answer resolver returned; the hostname
+// itself, not the resolved IP, is what TLS verification and SNI still
+// see further up the stack, since those are set from the original
+// request URL rather than from addr.
+func DialContext(resolver Resolver, base dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return base.DialContext(ctx, network, addr)
+		}
+
+		if net.ParseIP(host) != nil {
+			return base.DialContext(ctx, network, addr)
+		}
+
+		answers, err := resolver.Resolve(ctx, host)
+		if err != nil || len(answers) == 0 {
+			return base.DialContext(ctx, network, addr)
+		}
+
+		return base.DialContext(ctx, network, net.JoinHostPort(answers[0].IP.String(), port))
+	}
+}
@@ -0,0 +1,140 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
+)
+
+func TestEncodeDecodeQuery_RoundTrips(t *testing.T) {
+	query, err := encodeQuery(0x1234, "example.com", typeA, nil)
+	require.NoError(t, err)
+
+	// A query has no answers, but decodeAnswers must still parse the
+	// header and question section without error.
+	answers, err := decodeAnswers(mustBuildResponse(t, query, nil))
+	require.NoError(t, err)
+	assert.Empty(t, answers)
+}
+
+func TestDecodeAnswers_ParsesARecord(t *testing.T) {
+	query, err := encodeQuery(1, "example.com", typeA, nil)
+	require.NoError(t, err)
+
+	resp := mustBuildResponse(t, query, []byte{93, 184, 216, 34})
+	answers, err := decodeAnswers(resp)
+	require.NoError(t, err)
+	require.Len(t, answers, 1)
+	assert.Equal(t, "93.184.216.34", answers[0].IP.String())
+	assert.Equal(t, 60*time.Second, answers[0].TTL)
+}
+
+func TestEncodeQuery_ClientSubnetOption(t *testing.T) {
+	withSubnet, err := encodeQuery(1, "example.com", typeA, net.ParseIP("203.0.113.1"))
+	require.NoError(t, err)
+	withoutSubnet, err := encodeQuery(1, "example.com", typeA, nil)
+	require.NoError(t, err)
+
+	assert.Greater(t, len(withSubnet), len(withoutSubnet))
+}
+
+func TestStaticResolver(t *testing.T) {
+	r := NewStaticResolver(map[string]string{"internal.example": "10.0.0.1"})
+
+	answers, err := r.Resolve(context.Background(), "internal.example")
+	require.NoError(t, err)
+	require.Len(t, answers, 1)
+	assert.Equal(t, "10.0.0.1", answers[0].IP.String())
+
+	_, err = r.Resolve(context.Background(), "unknown.example")
+	assert.Error(t, err)
+}
+
+func TestChain_FallsBackToNextResolver(t *testing.T) {
+	failing := failingResolver{}
+	static := NewStaticResolver(map[string]string{"example.com": "127.0.0.1"})
+	chain := NewChain(failing, static)
+
+	answers, err := chain.Resolve(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", answers[0].IP.String())
+}
+
+func TestChain_ReturnsErrorWhenAllFail(t *testing.T) {
+	chain := NewChain(failingResolver{}, failingResolver{})
+
+	_, err := chain.Resolve(context.Background(), "example.com")
+	assert.Error(t, err)
+}
+
+func TestDoHResolver_ResolvesOverHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := readBody(t, r)
+		w.Header().Set("Content-Type", dnsMessageContentType)
+		w.Write(mustBuildResponse(t, query, []byte{127, 0, 0, 1}))
+	}))
+	defer server.Close()
+
+	static := NewStaticResolver(map[string]string{server.Listener.Addr().(*net.TCPAddr).IP.String(): server.Listener.Addr().(*net.TCPAddr).IP.String()})
+	resolver := NewDoHResolver(server.URL, static, cache.NewMemoryCache(), time.Minute, nil)
+
+	answers, err := resolver.Resolve(context.Background(), server.Listener.Addr().(*net.TCPAddr).IP.String())
+	require.NoError(t, err)
+	require.NotEmpty(t, answers)
+	assert.Equal(t, "127.0.0.1", answers[0].IP.String())
+}
+
+type failingResolver struct{}
+
+func (failingResolver) Resolve(ctx context.Context, host string) ([]Answer, error) {
+	return nil, errResolverUnavailable
+}
+
+var errResolverUnavailable = errors.New("resolver unavailable")
+
+// mustBuildResponse builds a minimal DNS response reusing query's header
+// ID and question section, with one A answer for ipv4 (nil means no
+// answers).
+func mustBuildResponse(t *testing.T, query []byte, ipv4 []byte) []byte {
+	t.Helper()
+	require.True(t, len(query) >= 12)
+
+	resp := make([]byte, len(query))
+	copy(resp, query)
+	resp[2] = 0x81 // response, recursion desired
+	resp[3] = 0x80 // recursion available, rcode 0
+
+	if ipv4 == nil {
+		return resp
+	}
+
+	resp[6] = 0
+	resp[7] = 1 // ANCOUNT = 1
+
+	answer := []byte{0xc0, 0x0c} // pointer to question's name
+	answer = appendUint16(answer, typeA)
+	answer = appendUint16(answer, classIN)
+	answer = append(answer, 0, 0, 0, 60) // TTL = 60s
+	answer = appendUint16(answer, uint16(len(ipv4)))
+	answer = append(answer, ipv4...)
+
+	return append(resp, answer...)
+}
+
+func readBody(t *testing.T, r *http.Request) []byte {
+	t.Helper()
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	return body
+}
@@ -0,0 +1,139 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/infra/cache"
+)
+
+// dnsMessageContentType is the media type RFC 8484 defines for a DNS
+// wire-format query or response body.
+const dnsMessageContentType = "application/dns-message"
+
+// DoHResolver resolves hostnames over DNS-over-HTTPS (RFC 8484), modeled
+// on v2fly's DoHNameServer: it POSTs a binary DNS query to a configured
+// upstream such as "https://1.1.1.1/dns-query" over a single shared
+// HTTP/2 client, and caches answers honoring whatever TTL the upstream
+// returned.
+type DoHResolver struct {
+	upstream      string
+	client        *http.Client
+	cache         cache.Cache
+	maxTTL        time.Duration
+	defaultSubnet net.IP
+}
+
+// NewDoHResolver builds a DoHResolver querying upstream. bootstrap
+// resolves upstream's own hostname - it is consulted once per dial, not
+// per query, and must not itself be a DoHResolver pointed at the same
+// upstream or every lookup would deadlock resolving its own endpoint.
+// store caches answers by host, A and AAAA together; maxTTL caps how long an answer is
+// kept even if the upstream advertised a longer one (0 leaves the
+// upstream's TTL as the only cap). defaultSubnet, when non-nil, is sent
+// as an EDNS0 client-subnet option on every query that doesn't override
+// it via WithClientSubnet.
+func NewDoHResolver(upstream string, bootstrap Resolver, store cache.Cache, maxTTL time.Duration, defaultSubnet net.IP) *DoHResolver {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	transport := &http.Transport{
+		ForceAttemptHTTP2: true,
+		DialContext:       DialContext(bootstrap, dialer),
+	}
+
+	return &DoHResolver{
+		upstream:      upstream,
+		client:        &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		cache:         store,
+		maxTTL:        maxTTL,
+		defaultSubnet: defaultSubnet,
+	}
+}
+
+// Resolve looks up host's A and AAAA records, preferring a cached answer
+// over a fresh query. ctx may carry a client-subnet override set by
+// WithClientSubnet.
+func (r *DoHResolver) Resolve(ctx context.Context, host string) ([]Answer, error) {
+	cacheKey := "doh:" + host
+	if cached, err := r.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
+		if answers, err := decodeCachedAnswers(cached); err == nil {
+			return answers, nil
+		}
+	}
+
+	subnet := clientSubnetFrom(ctx)
+	if subnet == nil {
+		subnet = r.defaultSubnet
+	}
+
+	var answers []Answer
+	for _, qtype := range []uint16{typeA, typeAAAA} {
+		queried, err := r.query(ctx, host, qtype, subnet)
+		if err != nil {
+			continue
+		}
+		answers = append(answers, queried...)
+	}
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("dns: no answers for %q", host)
+	}
+
+	r.cache.Set(ctx, cacheKey, encodeCachedAnswers(answers), cacheTTL(answers, r.maxTTL))
+	return answers, nil
+}
+
+// query runs a single A or AAAA lookup against the upstream.
+func (r *DoHResolver) query(ctx context.Context, host string, qtype uint16, subnet net.IP) ([]Answer, error) {
+	query, err := encodeQuery(uint16(rand.Intn(1<<16)), host, qtype, subnet)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.upstream, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dnsMessageContentType)
+	req.Header.Set("Accept", dnsMessageContentType)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dns: upstream %s returned %s", r.upstream, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAnswers(body)
+}
+
+// cacheTTL picks the TTL to store answers under: the shortest TTL among
+// them (so the cache never serves an answer longer than any record that
+// backs it said it should live), capped at maxTTL when maxTTL is set.
+func cacheTTL(answers []Answer, maxTTL time.Duration) time.Duration {
+	ttl := answers[0].TTL
+	for _, a := range answers[1:] {
+		if a.TTL < ttl {
+			ttl = a.TTL
+		}
+	}
+	if maxTTL > 0 && ttl > maxTTL {
+		ttl = maxTTL
+	}
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return ttl
+}
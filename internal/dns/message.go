@@ -0,0 +1,209 @@
+package dns
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DNS record types this package cares about; subscription/proxy hostnames
+// never need anything beyond an address record.
+const (
+	typeA    uint16 = 1
+	typeAAAA uint16 = 28
+	typeOPT  uint16 = 41
+	classIN  uint16 = 1
+)
+
+// optClientSubnet is the EDNS0 option code for the client-subnet
+// extension, RFC 7871.
+const optClientSubnet uint16 = 8
+
+// udpPayloadSize is advertised in the EDNS0 OPT record's CLASS field;
+// it has no bearing on a DoH query, which always travels over HTTPS, but
+// resolvers expect a plausible value there.
+const udpPayloadSize uint16 = 4096
+
+// encodeQuery builds a DNS wire-format query for host's A (qtype=typeA)
+// or AAAA (qtype=typeAAAA) records, attaching an EDNS0 OPT record with a
+// client-subnet option when subnet is non-nil.
+func encodeQuery(id uint16, host string, qtype uint16, subnet net.IP) ([]byte, error) {
+	name, err := encodeName(host)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, 64)
+	buf = appendUint16(buf, id)
+	buf = appendUint16(buf, 0x0100) // standard query, recursion desired
+	buf = appendUint16(buf, 1)      // QDCOUNT
+	buf = appendUint16(buf, 0)      // ANCOUNT
+	buf = appendUint16(buf, 0)      // NSCOUNT
+	arcount := uint16(0)
+	if subnet != nil {
+		arcount = 1
+	}
+	buf = appendUint16(buf, arcount) // ARCOUNT
+
+	buf = append(buf, name...)
+	buf = appendUint16(buf, qtype)
+	buf = appendUint16(buf, classIN)
+
+	if subnet != nil {
+		buf = appendClientSubnetOPT(buf, subnet)
+	}
+
+	return buf, nil
+}
+
+// appendClientSubnetOPT appends a root-named OPT RR carrying an EDNS0
+// client-subnet option for subnet.
+func appendClientSubnetOPT(buf []byte, subnet net.IP) []byte {
+	family := uint16(1)
+	addr := subnet.To4()
+	prefixBits := uint8(24)
+	if addr == nil {
+		family = 2
+		addr = subnet.To16()
+		prefixBits = 56
+	}
+	addrBytes := (int(prefixBits) + 7) / 8
+	if addrBytes > len(addr) {
+		addrBytes = len(addr)
+	}
+	addr = addr[:addrBytes]
+
+	option := make([]byte, 0, 4+len(addr))
+	option = appendUint16(option, family)
+	option = append(option, prefixBits, 0) // SOURCE PREFIX-LENGTH, SCOPE PREFIX-LENGTH
+	option = append(option, addr...)
+
+	rdata := make([]byte, 0, 4+len(option))
+	rdata = appendUint16(rdata, optClientSubnet)
+	rdata = appendUint16(rdata, uint16(len(option)))
+	rdata = append(rdata, option...)
+
+	buf = append(buf, 0) // root name
+	buf = appendUint16(buf, typeOPT)
+	buf = appendUint16(buf, udpPayloadSize)
+	buf = append(buf, 0, 0, 0, 0) // extended-RCODE/flags TTL, all zero
+	buf = appendUint16(buf, uint16(len(rdata)))
+	buf = append(buf, rdata...)
+	return buf
+}
+
+// encodeName converts host into DNS wire-format labels terminated by a
+// zero-length root label.
+func encodeName(host string) ([]byte, error) {
+	host = strings.TrimSuffix(host, ".")
+	var buf []byte
+	for _, label := range strings.Split(host, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("dns: invalid label %q in host %q", label, host)
+		}
+		buf = append(buf, byte(len(label)))
+		buf = append(buf, label...)
+	}
+	return append(buf, 0), nil
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+// decodeAnswers parses a DNS wire-format response, returning every A/AAAA
+// record's address and TTL. It skips CNAME and other record types, which
+// real-world DoH answers for CDN-backed hosts commonly include alongside
+// the address records.
+func decodeAnswers(msg []byte) ([]Answer, error) {
+	if len(msg) < 12 {
+		return nil, fmt.Errorf("dns: response too short (%d bytes)", len(msg))
+	}
+
+	rcode := msg[3] & 0x0f
+	if rcode != 0 {
+		return nil, fmt.Errorf("dns: response rcode %d", rcode)
+	}
+
+	qdcount := binary.BigEndian.Uint16(msg[4:6])
+	ancount := binary.BigEndian.Uint16(msg[6:8])
+
+	offset := 12
+	for i := uint16(0); i < qdcount; i++ {
+		var err error
+		offset, err = skipName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset += 4 // QTYPE + QCLASS
+	}
+
+	answers := make([]Answer, 0, ancount)
+	for i := uint16(0); i < ancount; i++ {
+		var err error
+		offset, err = skipName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if offset+10 > len(msg) {
+			return nil, fmt.Errorf("dns: truncated answer record")
+		}
+		rtype := binary.BigEndian.Uint16(msg[offset : offset+2])
+		ttl := binary.BigEndian.Uint32(msg[offset+4 : offset+8])
+		rdlength := int(binary.BigEndian.Uint16(msg[offset+8 : offset+10]))
+		offset += 10
+
+		if offset+rdlength > len(msg) {
+			return nil, fmt.Errorf("dns: truncated record data")
+		}
+		rdata := msg[offset : offset+rdlength]
+		offset += rdlength
+
+		switch rtype {
+		case typeA:
+			if len(rdata) != net.IPv4len {
+				return nil, fmt.Errorf("dns: malformed A record")
+			}
+			answers = append(answers, Answer{IP: net.IP(append([]byte(nil), rdata...)), TTL: secondsToTTL(ttl)})
+		case typeAAAA:
+			if len(rdata) != net.IPv6len {
+				return nil, fmt.Errorf("dns: malformed AAAA record")
+			}
+			answers = append(answers, Answer{IP: net.IP(append([]byte(nil), rdata...)), TTL: secondsToTTL(ttl)})
+		}
+	}
+
+	return answers, nil
+}
+
+// skipName advances past the name starting at offset, following at most
+// one compression pointer hop for the purpose of measuring its length in
+// the main message (the pointer target itself is never dereferenced
+// further, since skipName only needs to know where the name ends here).
+func skipName(msg []byte, offset int) (int, error) {
+	for {
+		if offset >= len(msg) {
+			return 0, fmt.Errorf("dns: name runs past end of message")
+		}
+		length := int(msg[offset])
+
+		switch {
+		case length == 0:
+			return offset + 1, nil
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(msg) {
+				return 0, fmt.Errorf("dns: truncated compression pointer")
+			}
+			return offset + 2, nil
+		default:
+			offset += 1 + length
+		}
+	}
+}
+
+func secondsToTTL(seconds uint32) time.Duration {
+	return time.Duration(seconds) * time.Second
+}
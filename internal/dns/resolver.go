@@ -0,0 +1,55 @@
+// Package dns resolves hostnames the way this tool needs them resolved:
+// reliably, and from a place the OS resolver might not reach, by talking
+// DNS-over-HTTPS instead of relying on /etc/resolv.conf. Resolver is the
+// shared abstraction; DoHResolver is the real implementation, modeled on
+// v2fly's DoHNameServer, and SystemResolver/StaticResolver/Chain let a
+// caller fall back to the OS resolver or a static hosts-like map when DoH
+// is unavailable or a hostname needs a fixed override.
+package dns
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// Answer is one resolved address plus how long it may be cached, taken
+// from the owning DNS record's own TTL.
+type Answer struct {
+	IP  net.IP
+	TTL time.Duration
+}
+
+// Resolver looks up host's addresses. Implementations must be safe for
+// concurrent use.
+type Resolver interface {
+	Resolve(ctx context.Context, host string) ([]Answer, error)
+}
+
+// clientSubnetKey namespaces the per-query EDNS0 client-subnet override
+// this package stores on a context.Context.
+type clientSubnetKey struct{}
+
+// WithClientSubnet returns a context that makes the next DoHResolver.Resolve
+// call send subnet as an EDNS0 client-subnet option (RFC 7871), so the
+// upstream can return answers appropriate for that network instead of
+// the DoH server's own vantage point.
+func WithClientSubnet(ctx context.Context, subnet net.IP) context.Context {
+	return context.WithValue(ctx, clientSubnetKey{}, subnet)
+}
+
+// clientSubnetFrom returns the subnet WithClientSubnet attached to ctx,
+// if any.
+func clientSubnetFrom(ctx context.Context) net.IP {
+	subnet, _ := ctx.Value(clientSubnetKey{}).(net.IP)
+	return subnet
+}
+
+// IPs extracts just the addresses from answers, discarding TTL.
+func IPs(answers []Answer) []net.IP {
+	ips := make([]net.IP, len(answers))
+	for i, a := range answers {
+		ips[i] = a.IP
+	}
+	return ips
+}
@@ -0,0 +1,38 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// StaticResolver answers from a fixed hostname -> IP map, an
+// /etc/hosts-like table. In the converter's resolver chain it is the
+// last fallback, after DoH and the OS resolver; only the lookup of a
+// DoH upstream's own hostname consults it first.
+type StaticResolver struct {
+	hosts map[string]net.IP
+}
+
+// NewStaticResolver builds a StaticResolver from hosts, a map of
+// hostname to literal IP string. An entry whose IP fails to parse is
+// dropped rather than failing construction.
+func NewStaticResolver(hosts map[string]string) *StaticResolver {
+	parsed := make(map[string]net.IP, len(hosts))
+	for host, raw := range hosts {
+		if ip := net.ParseIP(raw); ip != nil {
+			parsed[host] = ip
+		}
+	}
+	return &StaticResolver{hosts: parsed}
+}
+
+// Resolve returns host's configured IP, or an error if host has no
+// static entry.
+func (r *StaticResolver) Resolve(ctx context.Context, host string) ([]Answer, error) {
+	ip, ok := r.hosts[host]
+	if !ok {
+		return nil, fmt.Errorf("dns: no static entry for %q", host)
+	}
+	return []Answer{{IP: ip}}, nil
+}
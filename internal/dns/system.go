@@ -0,0 +1,35 @@
+package dns
+
+import (
+	"context"
+	"net"
+)
+
+// SystemResolver resolves through the OS's own resolver (net.Resolver),
+// the behavior this package exists to offer an alternative to. It is
+// used as a fallback for hosts that fail DoH, and to bootstrap a
+// DoHResolver's own upstream hostname.
+type SystemResolver struct {
+	resolver *net.Resolver
+}
+
+// NewSystemResolver builds a SystemResolver using net.DefaultResolver.
+func NewSystemResolver() *SystemResolver {
+	return &SystemResolver{resolver: net.DefaultResolver}
+}
+
+// Resolve looks up host via the OS resolver. The OS resolver doesn't
+// expose per-record TTLs, so every Answer's TTL is zero; callers that
+// cache SystemResolver answers should apply their own fixed TTL.
+func (r *SystemResolver) Resolve(ctx context.Context, host string) ([]Answer, error) {
+	ipAddrs, err := r.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make([]Answer, len(ipAddrs))
+	for i, addr := range ipAddrs {
+		answers[i] = Answer{IP: addr.IP}
+	}
+	return answers, nil
+}
@@ -18,6 +18,7 @@ const (
 	TypeHTTP         Type = "http"
 	TypeHTTPS        Type = "https"
 	TypeSocks5       Type = "socks5"
+	TypeSSH          Type = "ssh"
 )
 
 type Network string
@@ -69,8 +70,31 @@ type Proxy struct {
 	ObfsParam  string            `json:"obfs-param,omitempty" yaml:"obfs-param,omitempty"`
 	Protocol   string            `json:"protocol,omitempty" yaml:"protocol,omitempty"`
 	ProtocolParam string         `json:"protocol-param,omitempty" yaml:"protocol-param,omitempty"`
+	PrivateKey string            `json:"private-key,omitempty" yaml:"private-key,omitempty"`
+	PrivateKeyPassphrase string `json:"private-key-passphrase,omitempty" yaml:"private-key-passphrase,omitempty"`
+	Flow        string           `json:"flow,omitempty" yaml:"flow,omitempty"`
+	Fingerprint string           `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty" validate:"omitempty,fingerprint"`
+	PublicKey   string           `json:"public-key,omitempty" yaml:"public-key,omitempty"`
+	ShortID     string           `json:"short-id,omitempty" yaml:"short-id,omitempty"`
+	SpiderX     string           `json:"spider-x,omitempty" yaml:"spider-x,omitempty"`
+	ServiceName string           `json:"service-name,omitempty" yaml:"service-name,omitempty"`
+	GRPCMode    string           `json:"grpc-mode,omitempty" yaml:"grpc-mode,omitempty"`
+	HeaderType  string           `json:"header-type,omitempty" yaml:"header-type,omitempty"`
+	Ports       string           `json:"ports,omitempty" yaml:"ports,omitempty"`
+	HopInterval int              `json:"hop-interval,omitempty" yaml:"hop-interval,omitempty"`
+	ObfsPassword string          `json:"obfs-password,omitempty" yaml:"obfs-password,omitempty"`
+	PinSHA256   string           `json:"pin-sha256,omitempty" yaml:"pin-sha256,omitempty"`
+	FastOpen    bool             `json:"fast-open,omitempty" yaml:"fast-open,omitempty"`
 	CreatedAt  time.Time         `json:"created_at" yaml:"created_at"`
 	UpdatedAt  time.Time         `json:"updated_at" yaml:"updated_at"`
+	// Latency is the round-trip time in milliseconds from the last
+	// active health check, populated by healthcheck.Prober when
+	// converter.Options.Measure is set. Zero means unmeasured.
+	Latency int64 `json:"latency,omitempty" yaml:"latency,omitempty"`
+	// Alive reports whether the last active health check could reach
+	// this proxy's server:port at all. Meaningless until Latency has
+	// been populated at least once.
+	Alive bool `json:"alive,omitempty" yaml:"alive,omitempty"`
 }
 
 type Params map[string]interface{}
@@ -24,6 +24,8 @@ const (
 	RuleTypeIPCIDR      RuleType = "IP-CIDR"
 	RuleTypeIPCIDR6     RuleType = "IP-CIDR6"
 	RuleTypeGeoIP       RuleType = "GEOIP"
+	RuleTypeGeoSite     RuleType = "GEOSITE"
+	RuleTypeProcessName RuleType = "PROCESS-NAME"
 	RuleTypeUserAgent   RuleType = "USER-AGENT"
 	RuleTypeURLRegex    RuleType = "URL-REGEX"
 	RuleTypeFinal       RuleType = "FINAL"
@@ -46,6 +48,43 @@ type RuleSet struct {
 	Source    string    `json:"source,omitempty" yaml:"source,omitempty"`
 	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
 	Enabled   bool      `json:"enabled" yaml:"enabled"`
+	// Provider, when set, means this ruleset is a remote rule list rather
+	// than an inline one: generators emit a reference to it (Clash
+	// rule-providers, Surge RULE-SET, sing-box rule_set) instead of
+	// expanding Rules.
+	Provider *RuleProvider `json:"provider,omitempty" yaml:"provider,omitempty"`
+	// Policy is the proxy/outbound every match in Provider routes to. It is
+	// only meaningful when Provider is set.
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+// RuleProviderBehavior tells the client how to interpret a remote rule
+// list's entries.
+type RuleProviderBehavior string
+
+const (
+	BehaviorDomain    RuleProviderBehavior = "domain"
+	BehaviorIPCIDR    RuleProviderBehavior = "ipcidr"
+	BehaviorClassical RuleProviderBehavior = "classical"
+)
+
+// RuleProviderFormat is the wire format of a remote rule list.
+type RuleProviderFormat string
+
+const (
+	FormatYAML RuleProviderFormat = "yaml"
+	FormatText RuleProviderFormat = "text"
+	FormatMRS  RuleProviderFormat = "mrs"
+)
+
+// RuleProvider points a RuleSet at an externally hosted rule list instead
+// of inlining its rules, so clients fetch (and cache) it themselves.
+type RuleProvider struct {
+	URL      string               `json:"url" yaml:"url"`
+	Behavior RuleProviderBehavior `json:"behavior" yaml:"behavior"`
+	Format   RuleProviderFormat   `json:"format,omitempty" yaml:"format,omitempty"`
+	Interval int                  `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Path     string               `json:"path,omitempty" yaml:"path,omitempty"`
 }
 
 type ProxyGroupType string
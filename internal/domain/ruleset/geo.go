@@ -0,0 +1,12 @@
+package ruleset
+
+// GeoSiteEntries is the compiled domain list registered to a geosite tag
+// (e.g. "cn", "netflix"), split by how each entry should be matched.
+type GeoSiteEntries struct {
+	// Domains are exact-match hostnames ("full:" entries).
+	Domains []string `json:"domains,omitempty"`
+	// Suffixes match the hostname itself or any subdomain of it.
+	Suffixes []string `json:"suffixes,omitempty"`
+	// Keywords match any hostname containing the substring.
+	Keywords []string `json:"keywords,omitempty"`
+}
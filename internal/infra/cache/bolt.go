@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltBucket is the single bucket BoltCache stores all entries in.
+var boltBucket = []byte("cache")
+
+// boltEntry is the on-disk representation of a cached value. Expiry is a
+// UnixNano timestamp; zero means the entry never expires.
+type boltEntry struct {
+	Value  []byte `json:"value"`
+	Expiry int64  `json:"expiry"`
+}
+
+// BoltCache implements Cache on top of a BoltDB file, giving callers a
+// persistent store that survives process restarts.
+type BoltCache struct {
+	db *bolt.DB
+}
+
+// NewBoltCache opens (creating if necessary) a BoltDB file at path.
+func NewBoltCache(path string) (*BoltCache, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltCache{db: db}, nil
+}
+
+func (c *BoltCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+
+	err := c.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(boltBucket).Get([]byte(key))
+		if raw == nil {
+			return nil
+		}
+
+		var entry boltEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return err
+		}
+
+		if entry.Expiry != 0 && time.Now().UnixNano() > entry.Expiry {
+			return nil
+		}
+
+		value = entry.Value
+		return nil
+	})
+
+	return value, err
+}
+
+func (c *BoltCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expiry int64
+	if ttl > 0 {
+		expiry = time.Now().Add(ttl).UnixNano()
+	}
+
+	raw, err := json.Marshal(boltEntry{Value: value, Expiry: expiry})
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), raw)
+	})
+}
+
+func (c *BoltCache) Delete(ctx context.Context, key string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+}
+
+func (c *BoltCache) Health(ctx context.Context) error {
+	return c.db.View(func(tx *bolt.Tx) error {
+		return nil
+	})
+}
+
+// Flush deletes and recreates the cache bucket, discarding all entries.
+func (c *BoltCache) Flush(ctx context.Context) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(boltBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(boltBucket)
+		return err
+	})
+}
+
+// Close releases the underlying BoltDB file handle.
+func (c *BoltCache) Close() error {
+	return c.db.Close()
+}
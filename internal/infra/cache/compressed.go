@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"time"
+)
+
+// gzipMarker prefixes values that have been gzip-compressed by Compressed.
+// It is a NUL byte, which cannot appear as the first byte of the plain-text
+// JSON/YAML payloads this cache has historically stored, so reads of legacy,
+// uncompressed entries remain backward-compatible.
+const gzipMarker = byte(0x00)
+
+// Compressed wraps a Cache and transparently gzip-compresses values before
+// they reach the underlying backend, and decompresses them on the way out.
+// Values written by older, non-compressing callers (no marker byte) are
+// returned as-is.
+type Compressed struct {
+	inner Cache
+}
+
+// NewCompressed wraps inner with transparent gzip compression.
+func NewCompressed(inner Cache) *Compressed {
+	return &Compressed{inner: inner}
+}
+
+func (c *Compressed) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.inner.Get(ctx, key)
+	if err != nil || len(value) == 0 {
+		return value, err
+	}
+
+	if value[0] != gzipMarker {
+		return value, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(value[1:]))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func (c *Compressed) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var buf bytes.Buffer
+	buf.WriteByte(gzipMarker)
+
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(value); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return c.inner.Set(ctx, key, buf.Bytes(), ttl)
+}
+
+func (c *Compressed) Delete(ctx context.Context, key string) error {
+	return c.inner.Delete(ctx, key)
+}
+
+func (c *Compressed) Health(ctx context.Context) error {
+	return c.inner.Health(ctx)
+}
@@ -17,6 +17,27 @@ type Cache interface {
 	Health(ctx context.Context) error
 }
 
+// Flusher is implemented by cache backends that can discard every entry at
+// once. Callers type-assert for it rather than growing the core Cache
+// interface, since not every backend supports a cheap bulk clear.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// New selects a Cache backend based on cfg.Cache.Backend ("memory",
+// "redis", or "bolt"), falling back to MemoryCache for an empty or
+// unrecognized value so callers always get a usable cache.
+func New(cfg *config.Config) (Cache, error) {
+	switch cfg.Cache.Backend {
+	case "redis":
+		return NewRedisCache(&cfg.Redis), nil
+	case "bolt":
+		return NewBoltCache(cfg.Cache.BoltPath)
+	default:
+		return NewMemoryCache(), nil
+	}
+}
+
 // MemoryCache implements in-memory cache
 type MemoryCache struct {
 	data map[string]cacheItem
@@ -75,7 +96,16 @@ func (c *MemoryCache) Delete(ctx context.Context, key string) error {
 func (c *MemoryCache) Health(ctx context.Context) error {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
-	
+
+	return nil
+}
+
+// Flush discards every entry currently stored in the cache.
+func (c *MemoryCache) Flush(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.data = make(map[string]cacheItem)
 	return nil
 }
 
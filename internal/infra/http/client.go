@@ -3,43 +3,209 @@ package http
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	neturl "net/url"
+	"sync"
 	"time"
 
 	"github.com/imroc/req/v3"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/subconverter/subconverter-go/internal/dns"
 	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+	"github.com/subconverter/subconverter-go/internal/pkg/middleware"
 )
 
+// contextKey namespaces values this package stores on a context.Context.
+type contextKey string
+
+// UserAgentKey carries a per-request User-Agent override, set by the HTTP
+// layer from the incoming request and honored by Client.Get so that
+// providers returning device-specific subscriptions keep working.
+const UserAgentKey contextKey = "user-agent"
+
 // Client represents an HTTP client
 type Client struct {
-	client *req.Client
+	client      *req.Client
+	proxyURL    *neturl.URL
+	bearerToken string
+	authStore   *ProxyAuthStore
+	resolver    dns.Resolver
+
+	overrideMu sync.Mutex
+	overrides  map[string]*req.Client
 }
 
-// NewClient creates a new HTTP client
-func NewClient() *Client {
+// NewClient creates a new HTTP client. cfg.URL, when set, is dialed
+// through for every request; an empty cfg.URL falls back to
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY. cfg.AuthFile, if set, must validate
+// any Basic-auth credentials embedded in cfg.URL or NewClient returns an
+// error.
+func NewClient(cfg ProxyConfig) (*Client, error) {
+	var authStore *ProxyAuthStore
+	if cfg.AuthFile != "" {
+		store, err := LoadProxyAuthStore(cfg.AuthFile)
+		if err != nil {
+			return nil, err
+		}
+		authStore = store
+	}
+
+	proxyURL, err := resolveAndAuthorize(cfg.URL, authStore)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		proxyURL, err = environmentProxyURL()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve proxy from environment: %w", err)
+		}
+	}
+
+	client := newBaseClient(proxyURL, cfg.BearerToken, nil)
+
 	return &Client{
-		client: req.C().
-			SetTimeout(30 * time.Second).
-			SetUserAgent("SubConverter-Go/1.0").
-			EnableInsecureSkipVerify(),
+		client:      client,
+		proxyURL:    proxyURL,
+		bearerToken: cfg.BearerToken,
+		authStore:   authStore,
+		overrides:   make(map[string]*req.Client),
+	}, nil
+}
+
+// SetResolver installs resolver so every request this Client makes
+// (including per-request proxy overrides built after this call) dials
+// through an address resolved via resolver instead of the OS resolver,
+// the same way Prober.SetTrafficController installs an optional
+// cross-cutting hook after construction.
+func (c *Client) SetResolver(resolver dns.Resolver) {
+	c.overrideMu.Lock()
+	defer c.overrideMu.Unlock()
+
+	c.resolver = resolver
+	c.client = newBaseClient(c.proxyURL, c.bearerToken, resolver)
+	c.overrides = make(map[string]*req.Client)
+}
+
+// newBaseClient builds a req.Client with the standard timeout/UA/tracing
+// setup, dialing through proxyURL when non-nil and attaching
+// bearerToken as a Proxy-Authorization header when set. resolver, when
+// non-nil, resolves every request's hostname before the underlying
+// transport dials it, feeding the literal IP into the dial while leaving
+// the URL's hostname (and so TLS SNI and any Host header) untouched.
+func newBaseClient(proxyURL *neturl.URL, bearerToken string, resolver dns.Resolver) *req.Client {
+	client := req.C().
+		SetTimeout(30 * time.Second).
+		SetUserAgent("SubConverter-Go/1.0").
+		EnableInsecureSkipVerify()
+
+	if proxyURL != nil {
+		client.SetProxyURL(proxyURL.String())
+	}
+	if bearerToken != "" {
+		client.SetCommonHeader("Proxy-Authorization", "Bearer "+bearerToken)
+	}
+
+	if resolver != nil {
+		if transport, ok := client.GetClient().Transport.(*http.Transport); ok {
+			transport.DialContext = dns.DialContext(resolver, &net.Dialer{Timeout: 30 * time.Second})
+		}
+	}
+
+	// Instrument outbound requests so a span is emitted per fetch,
+	// letting Service.Convert's trace show exactly which provider URLs
+	// were slow or failed.
+	client.GetClient().Transport = otelhttp.NewTransport(client.GetClient().Transport)
+
+	return client
+}
+
+// resolveAndAuthorize parses raw (if non-empty) and, when authStore is
+// set, checks any Basic-auth credentials embedded in it against the
+// store. Returns a nil URL (not an error) when raw is empty.
+func resolveAndAuthorize(raw string, authStore *ProxyAuthStore) (*neturl.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	u, err := parseProxyURL(raw)
+	if err != nil {
+		return nil, err
 	}
+
+	if authStore != nil && u.User != nil {
+		password, _ := u.User.Password()
+		if !authStore.Authenticate(u.User.Username(), password) {
+			return nil, fmt.Errorf("proxy credentials for user %q rejected by auth file", u.User.Username())
+		}
+	}
+
+	return u, nil
+}
+
+// clientFor returns the req.Client that should handle a request given an
+// optional per-request proxy override, building and caching one the
+// first time a given override is seen.
+func (c *Client) clientFor(override string) (*req.Client, error) {
+	if override == "" {
+		return c.client, nil
+	}
+
+	c.overrideMu.Lock()
+	defer c.overrideMu.Unlock()
+
+	if client, ok := c.overrides[override]; ok {
+		return client, nil
+	}
+
+	proxyURL, err := resolveAndAuthorize(override, c.authStore)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newBaseClient(proxyURL, c.bearerToken, c.resolver)
+	c.overrides[override] = client
+	return client, nil
 }
 
 // Get performs a GET request
 func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
-	resp, err := c.client.R().
-		SetContext(ctx).
-		Get(url)
-	
+	body, _, err := c.GetWithHeaders(ctx, url)
+	return body, err
+}
+
+// GetWithHeaders performs a GET request and returns both the body and the
+// response headers, for callers that need provider metadata such as the
+// "subscription-userinfo" header.
+func (c *Client) GetWithHeaders(ctx context.Context, url string) (body []byte, headers http.Header, err error) {
+	start := time.Now()
+	defer func() {
+		middleware.HTTPFetchMetrics(time.Since(start), err == nil)
+	}()
+
+	override, _ := proxyOverride(ctx)
+	client, err := c.clientFor(override)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to fetch URL")
+		return nil, nil, errors.Wrap(err, "failed to configure proxy override")
 	}
-	
+
+	request := client.R().SetContext(ctx)
+	if userAgent, ok := ctx.Value(UserAgentKey).(string); ok && userAgent != "" {
+		request = request.SetHeader("User-Agent", userAgent)
+	}
+
+	resp, err := request.Get(url)
+
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to fetch URL")
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.BadRequest("FETCH_FAILED", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status))
+		return nil, nil, errors.BadRequest("FETCH_FAILED", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status))
 	}
-	
-	return resp.Bytes(), nil
+
+	return resp.Bytes(), resp.Header, nil
 }
 
 // Health checks the client health
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/infra/config"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+)
+
+// ShortLinkResolver expands a shortened subscription URL (e.g. from t.co,
+// bit.ly, or a self-hosted redirector) to its final destination.
+type ShortLinkResolver interface {
+	Resolve(ctx context.Context, rawURL string) (string, error)
+}
+
+// DefaultShortLinkResolver follows HTTP redirects (301/302/307/308) up to a
+// configurable number of hops, rejecting any hop that targets a host denied
+// by config (or, by default, an RFC1918/loopback address) to prevent SSRF.
+type DefaultShortLinkResolver struct {
+	client *http.Client
+	cfg    config.ShortLinkConfig
+}
+
+// NewDefaultShortLinkResolver creates a resolver that honors cfg and times
+// out each hop after timeout.
+func NewDefaultShortLinkResolver(cfg config.ShortLinkConfig, timeout time.Duration) *DefaultShortLinkResolver {
+	return &DefaultShortLinkResolver{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Resolve follows redirects starting at rawURL and returns the final URL.
+// If rawURL is not a redirect at all, it is returned unchanged.
+func (r *DefaultShortLinkResolver) Resolve(ctx context.Context, rawURL string) (string, error) {
+	if !r.cfg.Enabled {
+		return rawURL, nil
+	}
+
+	current := rawURL
+	maxHops := r.cfg.MaxHops
+	if maxHops <= 0 {
+		maxHops = 5
+	}
+
+	for hop := 0; hop < maxHops; hop++ {
+		if err := r.checkHost(current); err != nil {
+			return "", err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, current, nil)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to build short-link request")
+		}
+		if userAgent, ok := ctx.Value(UserAgentKey).(string); ok && userAgent != "" {
+			req.Header.Set("User-Agent", userAgent)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return "", errors.Wrap(err, fmt.Sprintf("failed to resolve short link: %s", current))
+		}
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+			location := resp.Header.Get("Location")
+			if location == "" {
+				return current, nil
+			}
+			next, err := url.Parse(location)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to parse redirect location")
+			}
+			base, err := url.Parse(current)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to parse current short-link URL")
+			}
+			current = base.ResolveReference(next).String()
+		default:
+			return current, nil
+		}
+	}
+
+	return "", errors.BadRequest("TOO_MANY_REDIRECTS", fmt.Sprintf("short link exceeded %d hops: %s", maxHops, rawURL))
+}
+
+// checkHost rejects hosts that resolve to RFC1918/loopback/link-local
+// addresses unless explicitly allow-listed, and rejects hosts explicitly
+// deny-listed regardless.
+func (r *DefaultShortLinkResolver) checkHost(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.BadRequest("INVALID_URL", fmt.Sprintf("invalid short-link URL: %s", rawURL))
+	}
+
+	host := u.Hostname()
+	for _, denied := range r.cfg.DenyHosts {
+		if strings.EqualFold(host, denied) {
+			return errors.BadRequest("HOST_DENIED", fmt.Sprintf("host is denied: %s", host))
+		}
+	}
+
+	for _, allowed := range r.cfg.AllowHosts {
+		if strings.EqualFold(host, allowed) {
+			return nil
+		}
+	}
+	if len(r.cfg.AllowHosts) > 0 {
+		return errors.BadRequest("HOST_NOT_ALLOWED", fmt.Sprintf("host is not allow-listed: %s", host))
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if isPrivateAddress(ip) {
+			return errors.BadRequest("SSRF_BLOCKED", fmt.Sprintf("refusing to follow redirect to private address: %s", host))
+		}
+		return nil
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to resolve host: %s", host))
+	}
+	for _, ip := range ips {
+		if isPrivateAddress(ip) {
+			return errors.BadRequest("SSRF_BLOCKED", fmt.Sprintf("refusing to follow redirect to private address: %s (%s)", host, ip))
+		}
+	}
+
+	return nil
+}
+
+func isPrivateAddress(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
@@ -0,0 +1,545 @@
+package queue
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/subconverter/subconverter-go/internal/app/converter"
+	"github.com/subconverter/subconverter-go/internal/infra/config"
+	"github.com/subconverter/subconverter-go/internal/pkg/errors"
+	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+)
+
+const (
+	amqpRetryExchange = ".retry"
+	amqpRetryQueue    = ".retry"
+	amqpDeadExchange  = ".dead"
+	amqpDeadQueue     = ".dead"
+
+	amqpReconnectMinBackoff = 500 * time.Millisecond
+	amqpReconnectMaxBackoff = 30 * time.Second
+
+	// amqpListLimit bounds how many dead-letter messages ListDead/Requeue
+	// scan per call: AMQP queues have no cursor/range primitive like
+	// Redis's LRANGE, so scanning is a Get loop that must terminate.
+	amqpListLimit = 1000
+)
+
+// AMQPQueue implements Queue on top of a RabbitMQ broker, with publisher
+// confirms, consumer acks tied to job completion, a dead-letter/retry
+// topology for failed jobs, and automatic reconnect on broker restarts.
+type AMQPQueue struct {
+	cfg config.AMQPConfig
+	log *logger.Logger
+
+	mu         sync.Mutex
+	conn       *amqp.Connection
+	channel    *amqp.Channel
+	confirm    chan amqp.Confirmation
+	deliveries <-chan amqp.Delivery
+
+	pendingMu sync.Mutex
+	pending   map[string]*amqpPending
+
+	closed chan struct{}
+}
+
+// amqpPending pairs a broker delivery awaiting ack/nack with the Job as
+// decoded at Pop time, so Complete/Ack/Nack don't have to re-unmarshal
+// delivery.Body (which doesn't reflect in-memory mutations like the
+// incremented Attempts counter).
+type amqpPending struct {
+	delivery amqp.Delivery
+	job      Job
+}
+
+// NewAMQPQueue creates a new RabbitMQ-backed queue and establishes the
+// exchange/queue/DLX topology described by cfg.
+func NewAMQPQueue(cfg config.AMQPConfig, log *logger.Logger) (*AMQPQueue, error) {
+	q := &AMQPQueue{
+		cfg:     cfg,
+		log:     log,
+		pending: make(map[string]*amqpPending),
+		closed:  make(chan struct{}),
+	}
+
+	if err := q.connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to amqp broker: %w", err)
+	}
+
+	go q.watchReconnect()
+
+	return q, nil
+}
+
+func (q *AMQPQueue) connect() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var conn *amqp.Connection
+	var err error
+	if q.cfg.TLS {
+		conn, err = amqp.DialTLS(q.cfg.URL, &tls.Config{MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = amqp.Dial(q.cfg.URL)
+	}
+	if err != nil {
+		return err
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		conn.Close()
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	if err := q.declareTopology(channel); err != nil {
+		channel.Close()
+		conn.Close()
+		return err
+	}
+
+	if err := channel.Qos(q.cfg.PrefetchCount, 0, false); err != nil {
+		channel.Close()
+		conn.Close()
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	// Consume once per channel and let every Pop read from the resulting
+	// delivery channel, rather than each Pop call registering its own
+	// consumer - repeated Consume calls on the same channel would each
+	// leak an abandoned consumer and strand up to PrefetchCount unacked
+	// deliveries in it.
+	deliveries, err := channel.Consume(q.cfg.Queue, "", false, false, false, false, nil)
+	if err != nil {
+		channel.Close()
+		conn.Close()
+		return fmt.Errorf("failed to start consuming: %w", err)
+	}
+
+	q.conn = conn
+	q.channel = channel
+	q.confirm = channel.NotifyPublish(make(chan amqp.Confirmation, 1))
+	q.deliveries = deliveries
+
+	return nil
+}
+
+// declareTopology sets up the main work queue plus a retry queue (whose
+// messages carry a per-message expiration set by Nack, computed from the
+// job's attempt count, and dead-letter back into the main exchange once
+// that expires) and a terminal dead-letter queue for jobs that keep
+// failing past MaxAttempts.
+func (q *AMQPQueue) declareTopology(channel *amqp.Channel) error {
+	if err := channel.ExchangeDeclare(q.cfg.Exchange, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
+	retryExchange := q.cfg.Exchange + amqpRetryExchange
+	if err := channel.ExchangeDeclare(retryExchange, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare retry exchange: %w", err)
+	}
+
+	deadExchange := q.cfg.Exchange + amqpDeadExchange
+	if err := channel.ExchangeDeclare(deadExchange, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead-letter exchange: %w", err)
+	}
+
+	if _, err := channel.QueueDeclare(q.cfg.Queue, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    retryExchange,
+		"x-dead-letter-routing-key": q.cfg.RoutingKey,
+	}); err != nil {
+		return fmt.Errorf("failed to declare work queue: %w", err)
+	}
+	if err := channel.QueueBind(q.cfg.Queue, q.cfg.RoutingKey, q.cfg.Exchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind work queue: %w", err)
+	}
+
+	retryQueue := q.cfg.Queue + amqpRetryQueue
+	if _, err := channel.QueueDeclare(retryQueue, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    q.cfg.Exchange,
+		"x-dead-letter-routing-key": q.cfg.RoutingKey,
+	}); err != nil {
+		return fmt.Errorf("failed to declare retry queue: %w", err)
+	}
+	if err := channel.QueueBind(retryQueue, q.cfg.RoutingKey, retryExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind retry queue: %w", err)
+	}
+
+	deadQueue := q.cfg.Queue + amqpDeadQueue
+	if _, err := channel.QueueDeclare(deadQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead-letter queue: %w", err)
+	}
+	if err := channel.QueueBind(deadQueue, q.cfg.RoutingKey, deadExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead-letter queue: %w", err)
+	}
+
+	return nil
+}
+
+// watchReconnect reconnects with exponential backoff whenever the broker
+// connection drops, so Worker.Start survives broker restarts transparently.
+func (q *AMQPQueue) watchReconnect() {
+	backoff := amqpReconnectMinBackoff
+
+	for {
+		q.mu.Lock()
+		conn := q.conn
+		q.mu.Unlock()
+
+		notifyClose := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+		select {
+		case <-q.closed:
+			return
+		case err := <-notifyClose:
+			if err != nil {
+				q.log.WithError(err).Warn("AMQP connection lost, reconnecting")
+			}
+		}
+
+		for {
+			select {
+			case <-q.closed:
+				return
+			case <-time.After(backoff):
+			}
+
+			if err := q.connect(); err != nil {
+				q.log.WithError(err).Warn("AMQP reconnect attempt failed")
+				backoff = nextBackoff(backoff)
+				continue
+			}
+
+			q.log.Info("AMQP connection re-established")
+			backoff = amqpReconnectMinBackoff
+			break
+		}
+	}
+}
+
+func nextBackoff(current time.Duration) time.Duration {
+	next := current * 2
+	if next > amqpReconnectMaxBackoff {
+		next = amqpReconnectMaxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(next) / 4))
+	return next + jitter
+}
+
+func (q *AMQPQueue) Push(ctx context.Context, job *Job) error {
+	if job.ID == "" {
+		job.ID = generateJobID()
+	}
+	job.CreatedAt = time.Now()
+	job.Status = "pending"
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	channel := q.channel
+	confirm := q.confirm
+	q.mu.Unlock()
+
+	if err := channel.PublishWithContext(ctx, q.cfg.Exchange, q.cfg.RoutingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    job.ID,
+		Timestamp:    job.CreatedAt,
+		Body:         data,
+	}); err != nil {
+		return fmt.Errorf("failed to publish job: %w", err)
+	}
+
+	select {
+	case confirmation := <-confirm:
+		if !confirmation.Ack {
+			return fmt.Errorf("broker did not confirm job %s", job.ID)
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (q *AMQPQueue) Pop(ctx context.Context) (*Job, error) {
+	q.mu.Lock()
+	deliveries := q.deliveries
+	q.mu.Unlock()
+
+	select {
+	case delivery, ok := <-deliveries:
+		if !ok {
+			return nil, fmt.Errorf("amqp delivery channel closed")
+		}
+
+		var job Job
+		if err := json.Unmarshal(delivery.Body, &job); err != nil {
+			delivery.Nack(false, false)
+			return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+		}
+		job.Status = "processing"
+		job.Attempts++
+		if job.MaxAttempts == 0 {
+			job.MaxAttempts = DefaultMaxAttempts
+		}
+
+		q.pendingMu.Lock()
+		q.pending[job.ID] = &amqpPending{delivery: delivery, job: job}
+		q.pendingMu.Unlock()
+
+		return &job, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (q *AMQPQueue) Complete(ctx context.Context, jobID string, result *converter.ConvertResponse) error {
+	return q.Ack(ctx, jobID)
+}
+
+// Ack acks the delivery backing jobID, permanently removing it from the
+// work queue.
+func (q *AMQPQueue) Ack(ctx context.Context, jobID string) error {
+	pending, ok := q.takePending(jobID)
+	if !ok {
+		return fmt.Errorf("no pending delivery for job %s", jobID)
+	}
+	return pending.delivery.Ack(false)
+}
+
+// Nack records jobErr against the job and either republishes it to the
+// retry exchange with a per-message expiration of retryAfter (so it
+// dead-letters back into the work queue once that elapses), or, once
+// Attempts reaches MaxAttempts, republishes it to the dead-letter
+// exchange instead and acks the original delivery either way — the
+// retry/dead-letter copy is now the durable record, not the original.
+func (q *AMQPQueue) Nack(ctx context.Context, jobID string, jobErr error, retryAfter time.Duration) error {
+	pending, ok := q.takePending(jobID)
+	if !ok {
+		return fmt.Errorf("no pending delivery for job %s", jobID)
+	}
+
+	job := pending.job
+	job.Error = jobErr.Error()
+	job.History = append(job.History, JobFailure{
+		Attempt:  job.Attempts,
+		Error:    jobErr.Error(),
+		FailedAt: time.Now(),
+	})
+
+	q.mu.Lock()
+	channel := q.channel
+	q.mu.Unlock()
+
+	if job.Attempts >= job.MaxAttempts {
+		job.Status = "dead"
+		data, err := json.Marshal(&job)
+		if err != nil {
+			pending.delivery.Nack(false, false)
+			return err
+		}
+
+		deadExchange := q.cfg.Exchange + amqpDeadExchange
+		if err := channel.PublishWithContext(ctx, deadExchange, q.cfg.RoutingKey, false, false, amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    job.ID,
+			Timestamp:    time.Now(),
+			Body:         data,
+		}); err != nil {
+			pending.delivery.Nack(false, false)
+			return fmt.Errorf("failed to publish dead-lettered job: %w", err)
+		}
+
+		q.log.WithError(jobErr).WithField("job_id", jobID).Warn("Job exhausted retry attempts, moved to dead-letter queue")
+		return pending.delivery.Ack(false)
+	}
+
+	job.Status = "pending"
+	data, err := json.Marshal(&job)
+	if err != nil {
+		pending.delivery.Nack(false, false)
+		return err
+	}
+
+	retryExchange := q.cfg.Exchange + amqpRetryExchange
+	if err := channel.PublishWithContext(ctx, retryExchange, q.cfg.RoutingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    job.ID,
+		Timestamp:    time.Now(),
+		Expiration:   strconv.FormatInt(retryAfter.Milliseconds(), 10),
+		Body:         data,
+	}); err != nil {
+		pending.delivery.Nack(false, false)
+		return fmt.Errorf("failed to publish job to retry queue: %w", err)
+	}
+
+	q.log.WithError(jobErr).WithFields(map[string]interface{}{
+		"job_id":      jobID,
+		"attempt":     job.Attempts,
+		"retry_after": retryAfter,
+	}).Warn("Job failed, scheduled for retry")
+	return pending.delivery.Ack(false)
+}
+
+func (q *AMQPQueue) Get(ctx context.Context, jobID string) (*Job, error) {
+	q.pendingMu.Lock()
+	pending, ok := q.pending[jobID]
+	q.pendingMu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	job := pending.job
+	return &job, nil
+}
+
+// ListDead drains up to amqpListLimit messages from the dead-letter queue
+// and immediately nacks each back with requeue=true, so this is a
+// non-destructive scan: no message is removed, only reordered to the
+// back of the queue.
+func (q *AMQPQueue) ListDead(ctx context.Context) ([]*Job, error) {
+	q.mu.Lock()
+	channel := q.channel
+	q.mu.Unlock()
+
+	deadQueue := q.cfg.Queue + amqpDeadQueue
+
+	var jobs []*Job
+	for i := 0; i < amqpListLimit; i++ {
+		delivery, ok, err := channel.Get(deadQueue, false)
+		if err != nil {
+			return jobs, fmt.Errorf("failed to read dead-letter queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		var job Job
+		if err := json.Unmarshal(delivery.Body, &job); err == nil {
+			jobs = append(jobs, &job)
+		}
+		delivery.Nack(false, true)
+	}
+	return jobs, nil
+}
+
+// Requeue scans the dead-letter queue for jobID (see ListDead's caveat
+// about scan order), resets its attempt counter, and republishes it to
+// the main exchange for another attempt.
+func (q *AMQPQueue) Requeue(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	channel := q.channel
+	q.mu.Unlock()
+
+	deadQueue := q.cfg.Queue + amqpDeadQueue
+
+	for i := 0; i < amqpListLimit; i++ {
+		delivery, ok, err := channel.Get(deadQueue, false)
+		if err != nil {
+			return fmt.Errorf("failed to read dead-letter queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		var job Job
+		if err := json.Unmarshal(delivery.Body, &job); err != nil || job.ID != jobID {
+			delivery.Nack(false, true)
+			continue
+		}
+
+		job.Attempts = 0
+		job.Status = "pending"
+		data, err := json.Marshal(&job)
+		if err != nil {
+			delivery.Nack(false, true)
+			return err
+		}
+
+		if err := channel.PublishWithContext(ctx, q.cfg.Exchange, q.cfg.RoutingKey, false, false, amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    job.ID,
+			Timestamp:    time.Now(),
+			Body:         data,
+		}); err != nil {
+			delivery.Nack(false, true)
+			return fmt.Errorf("failed to republish job: %w", err)
+		}
+
+		return delivery.Ack(false)
+	}
+
+	return fmt.Errorf("job %s is not in the dead-letter queue", jobID)
+}
+
+func (q *AMQPQueue) takePending(jobID string) (*amqpPending, bool) {
+	q.pendingMu.Lock()
+	defer q.pendingMu.Unlock()
+	pending, ok := q.pending[jobID]
+	if ok {
+		delete(q.pending, jobID)
+	}
+	return pending, ok
+}
+
+// errScheduleUnsupported is returned by every AMQPQueue scheduling
+// method: RabbitMQ's topology here is pure pub/sub with no range-query
+// primitive to scan for due jobs, unlike Redis's sorted set, so
+// scheduling is only offered on MemoryQueue and RedisQueue.
+var errScheduleUnsupported = errors.NewWithStatus(
+	"SCHEDULING_UNSUPPORTED",
+	"AMQPQueue does not support scheduled jobs; use MemoryQueue or RedisQueue",
+	http.StatusNotImplemented,
+)
+
+func (q *AMQPQueue) Schedule(ctx context.Context, job *ScheduledJob) error {
+	return errScheduleUnsupported
+}
+
+func (q *AMQPQueue) Unschedule(ctx context.Context, id string) error {
+	return errScheduleUnsupported
+}
+
+func (q *AMQPQueue) ListSchedules(ctx context.Context) ([]*ScheduledJob, error) {
+	return nil, errScheduleUnsupported
+}
+
+func (q *AMQPQueue) PopDueSchedules(ctx context.Context, now time.Time, leaseFor time.Duration, limit int) ([]*ScheduledJob, error) {
+	return nil, errScheduleUnsupported
+}
+
+// Close shuts down the AMQP connection and stops the reconnect watcher.
+func (q *AMQPQueue) Close() error {
+	close(q.closed)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.channel != nil {
+		q.channel.Close()
+	}
+	if q.conn != nil {
+		return q.conn.Close()
+	}
+	return nil
+}
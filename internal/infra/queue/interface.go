@@ -4,23 +4,71 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/subconverter/subconverter-go/internal/app/converter"
 	"github.com/subconverter/subconverter-go/internal/infra/config"
 	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+	"github.com/subconverter/subconverter-go/internal/pkg/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Job represents a conversion job
+// tracer emits a span around each Worker.processJob call, which
+// converter.Service.Convert's own tracer (see converter.tracer) then
+// nests its fetch/parse/generate spans under.
+var tracer = otel.Tracer("github.com/subconverter/subconverter-go/internal/infra/queue")
+
+// DefaultMaxAttempts is the Attempts budget Push gives a Job that didn't
+// set one explicitly.
+const DefaultMaxAttempts = 5
+
+const (
+	jobBackoffBase = 2 * time.Second
+	jobBackoffMax  = 5 * time.Minute
+)
+
+// jobBackoff returns the delay to wait before redelivering a job that has
+// failed attempt times: base*2^attempt, capped at jobBackoffMax and
+// jittered by up to half the delay so a burst of failures doesn't retry
+// in lockstep.
+func jobBackoff(attempt int) time.Duration {
+	d := jobBackoffBase * time.Duration(1<<uint(attempt))
+	if d <= 0 || d > jobBackoffMax {
+		d = jobBackoffMax
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// JobFailure records one failed attempt at running a Job, kept in
+// Job.History so ListDead has enough context to diagnose why a job died
+// without needing the original log lines.
+type JobFailure struct {
+	Attempt  int       `json:"attempt"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// Job represents a conversion job. Request.Options.Proxy, if set, routes
+// this job's subscription fetches through a specific upstream proxy;
+// otherwise the Worker's Service falls back to its configured
+// FetcherConfig.ProxyURL, so every worker process inherits the same
+// outbound proxy regardless of which queue backend dispatched the job.
 type Job struct {
-	ID        string                     `json:"id"`
-	Type      string                     `json:"type"`
-	Request   converter.ConvertRequest   `json:"request"`
-	CreatedAt time.Time                  `json:"created_at"`
-	Status    string                     `json:"status"`
-	Result    *converter.ConvertResponse `json:"result,omitempty"`
-	Error     string                     `json:"error,omitempty"`
+	ID          string                     `json:"id"`
+	Type        string                     `json:"type"`
+	Request     converter.ConvertRequest   `json:"request"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	Status      string                     `json:"status"`
+	Result      *converter.ConvertResponse `json:"result,omitempty"`
+	Error       string                     `json:"error,omitempty"`
+	Attempts    int                        `json:"attempts"`
+	MaxAttempts int                         `json:"max_attempts"`
+	History     []JobFailure               `json:"history,omitempty"`
 }
 
 // Queue defines the interface for job queue operations
@@ -28,21 +76,56 @@ type Queue interface {
 	Push(ctx context.Context, job *Job) error
 	Pop(ctx context.Context) (*Job, error)
 	Complete(ctx context.Context, jobID string, result *converter.ConvertResponse) error
-	Fail(ctx context.Context, jobID string, err error) error
+	// Ack permanently removes jobID from the queue's in-flight tracking
+	// without recording a result. Complete calls it after persisting a
+	// successful result; Worker doesn't need to call it directly.
+	Ack(ctx context.Context, jobID string) error
+	// Nack reports that jobID failed with jobErr. If the job's Attempts
+	// is still under MaxAttempts, the implementation redelivers it after
+	// retryAfter; once Attempts reaches MaxAttempts the job instead moves
+	// to the dead-letter list, retrievable via ListDead.
+	Nack(ctx context.Context, jobID string, jobErr error, retryAfter time.Duration) error
 	Get(ctx context.Context, jobID string) (*Job, error)
+
+	// ListDead returns every job that exhausted MaxAttempts, most recent
+	// first where the backend can cheaply offer that order.
+	ListDead(ctx context.Context) ([]*Job, error)
+	// Requeue moves a dead-lettered job back onto the queue with its
+	// attempt counter reset, for operators to retry by hand via `subctl
+	// queue requeue` once the underlying issue is fixed.
+	Requeue(ctx context.Context, jobID string) error
+
+	// Schedule registers or updates a recurring job definition.
+	Schedule(ctx context.Context, job *ScheduledJob) error
+	// Unschedule removes a recurring job definition by ID.
+	Unschedule(ctx context.Context, id string) error
+	// ListSchedules returns every registered recurring job.
+	ListSchedules(ctx context.Context) ([]*ScheduledJob, error)
+	// PopDueSchedules atomically claims up to limit recurring jobs whose
+	// NextRunAt has passed, leasing each for leaseFor so concurrent
+	// Scheduler instances sharing a queue backend don't double-run the
+	// same schedule.
+	PopDueSchedules(ctx context.Context, now time.Time, leaseFor time.Duration, limit int) ([]*ScheduledJob, error)
 }
 
-// MemoryQueue implements in-memory job queue
+// MemoryQueue implements in-memory job queue. jobs (and dead) are guarded
+// by mu since Pop's consumers and Push's producers run on different
+// goroutines from the start.
 type MemoryQueue struct {
-	queue    chan *Job
-	jobs     map[string]*Job
+	queue     chan *Job
+	mu        sync.RWMutex
+	jobs      map[string]*Job
+	dead      map[string]*Job
+	schedules *memorySchedules
 }
 
 // NewMemoryQueue creates a new in-memory queue
 func NewMemoryQueue() *MemoryQueue {
 	return &MemoryQueue{
-		queue: make(chan *Job, 1000),
-		jobs:  make(map[string]*Job),
+		queue:     make(chan *Job, 1000),
+		jobs:      make(map[string]*Job),
+		dead:      make(map[string]*Job),
+		schedules: newMemorySchedules(),
 	}
 }
 
@@ -50,9 +133,14 @@ func (q *MemoryQueue) Push(ctx context.Context, job *Job) error {
 	job.ID = generateJobID()
 	job.CreatedAt = time.Now()
 	job.Status = "pending"
-	
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+
+	q.mu.Lock()
 	q.jobs[job.ID] = job
-	
+	q.mu.Unlock()
+
 	select {
 	case q.queue <- job:
 		return nil
@@ -64,7 +152,10 @@ func (q *MemoryQueue) Push(ctx context.Context, job *Job) error {
 func (q *MemoryQueue) Pop(ctx context.Context) (*Job, error) {
 	select {
 	case job := <-q.queue:
+		q.mu.Lock()
 		job.Status = "processing"
+		job.Attempts++
+		q.mu.Unlock()
 		return job, nil
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -72,6 +163,8 @@ func (q *MemoryQueue) Pop(ctx context.Context) (*Job, error) {
 }
 
 func (q *MemoryQueue) Complete(ctx context.Context, jobID string, result *converter.ConvertResponse) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	if job, exists := q.jobs[jobID]; exists {
 		job.Status = "completed"
 		job.Result = result
@@ -79,25 +172,112 @@ func (q *MemoryQueue) Complete(ctx context.Context, jobID string, result *conver
 	return nil
 }
 
-func (q *MemoryQueue) Fail(ctx context.Context, jobID string, err error) error {
-	if job, exists := q.jobs[jobID]; exists {
-		job.Status = "failed"
-		job.Error = err.Error()
+func (q *MemoryQueue) Ack(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.jobs, jobID)
+	return nil
+}
+
+func (q *MemoryQueue) Nack(ctx context.Context, jobID string, jobErr error, retryAfter time.Duration) error {
+	q.mu.Lock()
+	job, exists := q.jobs[jobID]
+	if !exists {
+		q.mu.Unlock()
+		return nil
 	}
+
+	job.History = append(job.History, JobFailure{
+		Attempt:  job.Attempts,
+		Error:    jobErr.Error(),
+		FailedAt: time.Now(),
+	})
+	job.Error = jobErr.Error()
+
+	if job.Attempts >= job.MaxAttempts {
+		job.Status = "dead"
+		delete(q.jobs, jobID)
+		q.dead[jobID] = job
+		q.mu.Unlock()
+		return nil
+	}
+
+	job.Status = "pending"
+	q.mu.Unlock()
+
+	time.AfterFunc(retryAfter, func() {
+		select {
+		case q.queue <- job:
+		case <-ctx.Done():
+		}
+	})
 	return nil
 }
 
 func (q *MemoryQueue) Get(ctx context.Context, jobID string) (*Job, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
 	if job, exists := q.jobs[jobID]; exists {
 		return job, nil
 	}
+	if job, exists := q.dead[jobID]; exists {
+		return job, nil
+	}
 	return nil, nil
 }
 
-// RedisQueue implements Redis-based job queue
+func (q *MemoryQueue) ListDead(ctx context.Context) ([]*Job, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	out := make([]*Job, 0, len(q.dead))
+	for _, job := range q.dead {
+		out = append(out, job)
+	}
+	return out, nil
+}
+
+func (q *MemoryQueue) Requeue(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	job, exists := q.dead[jobID]
+	if !exists {
+		q.mu.Unlock()
+		return fmt.Errorf("job %s is not in the dead-letter list", jobID)
+	}
+	delete(q.dead, jobID)
+	job.Attempts = 0
+	job.Status = "pending"
+	q.jobs[jobID] = job
+	q.mu.Unlock()
+
+	select {
+	case q.queue <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+const (
+	// redisVisibilityTimeout bounds how long a job may sit in the
+	// processing list before the reaper assumes its worker died and
+	// returns it to the main queue for redelivery.
+	redisVisibilityTimeout = 5 * time.Minute
+	redisReaperInterval    = 10 * time.Second
+	// redisDeadList matches the literal key the backlog asked for, so
+	// operators inspecting Redis directly find jobs without reading code.
+	redisDeadList = "subconverter:jobs:dead"
+)
+
+// RedisQueue implements Redis-based job queue. Pop uses BRPOPLPUSH to move
+// a job into a processing list instead of BRPOP's plain pop, so a worker
+// that crashes mid-job doesn't silently lose it: the reaper goroutine
+// notices the job outlived redisVisibilityTimeout in the processing list
+// and redelivers it.
 type RedisQueue struct {
 	client *redis.Client
 	prefix string
+
+	stop chan struct{}
 }
 
 // NewRedisQueue creates a new Redis queue
@@ -107,109 +287,256 @@ func NewRedisQueue(cfg config.RedisConfig) (*RedisQueue, error) {
 		Password: cfg.Password,
 		DB:       cfg.Database,
 	})
-	
-	return &RedisQueue{
+
+	q := &RedisQueue{
 		client: client,
 		prefix: "subconverter:jobs:",
-	}, nil
+		stop:   make(chan struct{}),
+	}
+	go q.reaper()
+
+	return q, nil
 }
 
+func (q *RedisQueue) queueKey() string      { return q.prefix + "queue" }
+func (q *RedisQueue) processingKey() string { return q.prefix + "processing" }
+func (q *RedisQueue) deadlineKey() string   { return q.prefix + "processing:deadline" }
+func (q *RedisQueue) retryKey() string      { return q.prefix + "retry" }
+
 func (q *RedisQueue) Push(ctx context.Context, job *Job) error {
 	if job.ID == "" {
 		job.ID = generateJobID()
 	}
 	job.CreatedAt = time.Now()
 	job.Status = "pending"
-	
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+
 	data, err := json.Marshal(job)
 	if err != nil {
 		return err
 	}
-	
+
 	pipe := q.client.Pipeline()
 	pipe.Set(ctx, q.prefix+job.ID, data, 24*time.Hour)
-	pipe.LPush(ctx, q.prefix+"queue", job.ID)
+	pipe.LPush(ctx, q.queueKey(), job.ID)
 	_, err = pipe.Exec(ctx)
-	
+
 	return err
 }
 
 func (q *RedisQueue) Pop(ctx context.Context) (*Job, error) {
-	result, err := q.client.BRPop(ctx, 0, q.prefix+"queue").Result()
+	jobID, err := q.client.BRPopLPush(ctx, q.queueKey(), q.processingKey(), 0).Result()
 	if err != nil {
 		return nil, err
 	}
-	
-	if len(result) < 2 {
+	if jobID == "" {
 		return nil, nil
 	}
-	
-	jobID := result[1]
+
+	job, err := q.loadJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = "processing"
+	job.Attempts++
+
+	pipe := q.client.Pipeline()
+	pipe.ZAdd(ctx, q.deadlineKey(), redis.Z{
+		Score:  float64(time.Now().Add(redisVisibilityTimeout).Unix()),
+		Member: jobID,
+	})
+	q.saveJobIn(ctx, pipe, job)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (q *RedisQueue) loadJob(ctx context.Context, jobID string) (*Job, error) {
 	data, err := q.client.Get(ctx, q.prefix+jobID).Bytes()
 	if err != nil {
 		return nil, err
 	}
-	
+
 	var job Job
 	if err := json.Unmarshal(data, &job); err != nil {
 		return nil, err
 	}
-	
-	job.Status = "processing"
-	data, _ = json.Marshal(job)
-	q.client.Set(ctx, q.prefix+jobID, data, 24*time.Hour)
-	
 	return &job, nil
 }
 
-func (q *RedisQueue) Complete(ctx context.Context, jobID string, result *converter.ConvertResponse) error {
-	data, err := q.client.Get(ctx, q.prefix+jobID).Bytes()
+func (q *RedisQueue) saveJobIn(ctx context.Context, pipe redis.Pipeliner, job *Job) {
+	data, _ := json.Marshal(job)
+	pipe.Set(ctx, q.prefix+job.ID, data, 24*time.Hour)
+}
+
+func (q *RedisQueue) saveJob(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
 	if err != nil {
 		return err
 	}
-	
-	var job Job
-	if err := json.Unmarshal(data, &job); err != nil {
+	return q.client.Set(ctx, q.prefix+job.ID, data, 24*time.Hour).Err()
+}
+
+func (q *RedisQueue) Complete(ctx context.Context, jobID string, result *converter.ConvertResponse) error {
+	job, err := q.loadJob(ctx, jobID)
+	if err != nil {
 		return err
 	}
-	
+
 	job.Status = "completed"
 	job.Result = result
-	data, _ = json.Marshal(job)
-	
-	return q.client.Set(ctx, q.prefix+jobID, data, 24*time.Hour).Err()
+	if err := q.saveJob(ctx, job); err != nil {
+		return err
+	}
+
+	return q.Ack(ctx, jobID)
 }
 
-func (q *RedisQueue) Fail(ctx context.Context, jobID string, err error) error {
-	data, err := q.client.Get(ctx, q.prefix+jobID).Bytes()
+// Ack removes jobID from the processing list and its visibility deadline,
+// which is all that's needed once the job's outcome (success or
+// dead-letter) has already been persisted.
+func (q *RedisQueue) Ack(ctx context.Context, jobID string) error {
+	pipe := q.client.Pipeline()
+	pipe.LRem(ctx, q.processingKey(), 1, jobID)
+	pipe.ZRem(ctx, q.deadlineKey(), jobID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (q *RedisQueue) Nack(ctx context.Context, jobID string, jobErr error, retryAfter time.Duration) error {
+	job, err := q.loadJob(ctx, jobID)
 	if err != nil {
 		return err
 	}
-	
-	var job Job
-	if err := json.Unmarshal(data, &job); err != nil {
-		return err
+
+	job.Error = jobErr.Error()
+	job.History = append(job.History, JobFailure{
+		Attempt:  job.Attempts,
+		Error:    jobErr.Error(),
+		FailedAt: time.Now(),
+	})
+
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = DefaultMaxAttempts
 	}
-	
-	job.Status = "failed"
-	job.Error = err.Error()
-	data, _ = json.Marshal(job)
-	
-	return q.client.Set(ctx, q.prefix+jobID, data, 24*time.Hour).Err()
+
+	pipe := q.client.Pipeline()
+	pipe.LRem(ctx, q.processingKey(), 1, jobID)
+	pipe.ZRem(ctx, q.deadlineKey(), jobID)
+
+	if job.Attempts >= job.MaxAttempts {
+		job.Status = "dead"
+		q.saveJobIn(ctx, pipe, job)
+		pipe.LPush(ctx, redisDeadList, jobID)
+	} else {
+		job.Status = "pending"
+		q.saveJobIn(ctx, pipe, job)
+		pipe.ZAdd(ctx, q.retryKey(), redis.Z{
+			Score:  float64(time.Now().Add(retryAfter).Unix()),
+			Member: jobID,
+		})
+	}
+
+	_, err = pipe.Exec(ctx)
+	return err
 }
 
 func (q *RedisQueue) Get(ctx context.Context, jobID string) (*Job, error) {
-	data, err := q.client.Get(ctx, q.prefix+jobID).Bytes()
+	return q.loadJob(ctx, jobID)
+}
+
+func (q *RedisQueue) ListDead(ctx context.Context) ([]*Job, error) {
+	ids, err := q.client.LRange(ctx, redisDeadList, 0, -1).Result()
 	if err != nil {
 		return nil, err
 	}
-	
-	var job Job
-	if err := json.Unmarshal(data, &job); err != nil {
-		return nil, err
+
+	out := make([]*Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := q.loadJob(ctx, id)
+		if err != nil {
+			continue
+		}
+		out = append(out, job)
 	}
-	
-	return &job, nil
+	return out, nil
+}
+
+func (q *RedisQueue) Requeue(ctx context.Context, jobID string) error {
+	removed, err := q.client.LRem(ctx, redisDeadList, 1, jobID).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("job %s is not in the dead-letter list", jobID)
+	}
+
+	job, err := q.loadJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	job.Attempts = 0
+	job.Status = "pending"
+
+	pipe := q.client.Pipeline()
+	q.saveJobIn(ctx, pipe, job)
+	pipe.LPush(ctx, q.queueKey(), jobID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// reaper periodically redelivers jobs whose visibility timeout expired
+// (their worker likely crashed before Ack/Nack) and promotes jobs whose
+// backoff delay from Nack has elapsed back onto the main queue.
+func (q *RedisQueue) reaper() {
+	ticker := time.NewTicker(redisReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+			ctx := context.Background()
+			q.reapExpired(ctx, q.deadlineKey(), q.processingKey())
+			q.reapExpired(ctx, q.retryKey(), "")
+		}
+	}
+}
+
+// reapExpired requeues every member of zsetKey whose score (a unix
+// timestamp) has passed. When fromList is set, the member is also
+// removed from that in-flight list, since expiry there means the job's
+// visibility timeout lapsed rather than its retry backoff.
+func (q *RedisQueue) reapExpired(ctx context.Context, zsetKey, fromList string) {
+	due, err := q.client.ZRangeByScore(ctx, zsetKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("%d", time.Now().Unix()),
+	}).Result()
+	if err != nil || len(due) == 0 {
+		return
+	}
+
+	for _, jobID := range due {
+		pipe := q.client.Pipeline()
+		pipe.ZRem(ctx, zsetKey, jobID)
+		if fromList != "" {
+			pipe.LRem(ctx, fromList, 1, jobID)
+		}
+		pipe.LPush(ctx, q.queueKey(), jobID)
+		pipe.Exec(ctx)
+	}
+}
+
+// Close stops the reaper goroutine and the underlying Redis client.
+func (q *RedisQueue) Close() error {
+	close(q.stop)
+	return q.client.Close()
 }
 
 // Worker processes jobs from the queue
@@ -270,23 +597,47 @@ func (w *Worker) worker(ctx context.Context, id int) {
 }
 
 func (w *Worker) processJob(ctx context.Context, job *Job) {
+	ctx, span := tracer.Start(ctx, "queue.processJob", trace.WithAttributes(
+		attribute.String("job.id", job.ID),
+		attribute.String("job.target", job.Request.Target),
+	))
+	defer span.End()
+
+	start := time.Now()
 	w.log.WithFields(map[string]interface{}{
 		"job_id": job.ID,
 		"type":   job.Type,
 	}).Info("Processing job")
-	
+
 	result, err := w.service.Convert(ctx, &job.Request)
 	if err != nil {
-		w.log.WithError(err).Error("Job failed")
-		w.queue.Fail(ctx, job.ID, err)
+		delay := jobBackoff(job.Attempts)
+		status := "retried"
+		if job.Attempts >= job.MaxAttempts {
+			status = "dead"
+		}
+		span.SetAttributes(attribute.String("job.status", status))
+		middleware.JobMetrics(job.Request.Target, status, time.Since(start))
+
+		w.log.WithError(err).WithFields(map[string]interface{}{
+			"job_id":      job.ID,
+			"attempt":     job.Attempts,
+			"retry_after": delay,
+		}).Error("Job failed")
+		if nackErr := w.queue.Nack(ctx, job.ID, err, delay); nackErr != nil {
+			w.log.WithError(nackErr).Error("Failed to nack job")
+		}
 		return
 	}
-	
+
 	if err := w.queue.Complete(ctx, job.ID, result); err != nil {
 		w.log.WithError(err).Error("Failed to complete job")
 		return
 	}
-	
+
+	span.SetAttributes(attribute.String("job.status", "completed"))
+	middleware.JobMetrics(job.Request.Target, "completed", time.Since(start))
+
 	w.log.WithFields(map[string]interface{}{
 		"job_id": job.ID,
 		"proxies": len(result.Proxies),
@@ -0,0 +1,64 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/subconverter/subconverter-go/internal/pkg/middleware"
+)
+
+// DepthReporter is implemented by queue backends that can cheaply report
+// how many jobs are currently waiting to be popped, for StartDepthMetrics
+// to poll into the subconverter_queue_depth gauge.
+type DepthReporter interface {
+	Depth(ctx context.Context) (int64, error)
+}
+
+// Depth returns the number of jobs buffered in the in-memory channel.
+func (q *MemoryQueue) Depth(ctx context.Context) (int64, error) {
+	return int64(len(q.queue)), nil
+}
+
+// Depth runs LLEN against the main work list.
+func (q *RedisQueue) Depth(ctx context.Context) (int64, error) {
+	return q.client.LLen(ctx, q.queueKey()).Result()
+}
+
+// Depth inspects the work queue's current message count.
+func (q *AMQPQueue) Depth(ctx context.Context) (int64, error) {
+	q.mu.Lock()
+	channel := q.channel
+	q.mu.Unlock()
+
+	info, err := channel.QueueInspect(q.cfg.Queue)
+	if err != nil {
+		return 0, err
+	}
+	return int64(info.Messages), nil
+}
+
+// StartDepthMetrics polls q's queue depth every interval and publishes it
+// under backend via middleware.SetQueueDepth, until ctx is done. Queue
+// implementations that don't satisfy DepthReporter are silently skipped.
+func StartDepthMetrics(ctx context.Context, q Queue, backend string, interval time.Duration) {
+	reporter, ok := q.(DepthReporter)
+	if !ok {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if depth, err := reporter.Depth(ctx); err == nil {
+					middleware.SetQueueDepth(backend, float64(depth))
+				}
+			}
+		}
+	}()
+}
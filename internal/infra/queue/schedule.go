@@ -0,0 +1,327 @@
+package queue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+
+	"github.com/subconverter/subconverter-go/internal/app/converter"
+	"github.com/subconverter/subconverter-go/internal/pkg/logger"
+)
+
+// ScheduledJob is a recurring conversion registered against a cron
+// expression (robfig/cron v3 standard 5-field syntax, e.g. "0 * * * *"
+// for hourly), instead of the one-off Job a single Queue.Push enqueues.
+type ScheduledJob struct {
+	ID         string                   `json:"id"`
+	Expression string                   `json:"expression"`
+	Request    converter.ConvertRequest `json:"request"`
+	// WebhookURL, if set, receives a POST of the run's outcome after
+	// every execution.
+	WebhookURL string    `json:"webhook_url,omitempty"`
+	NextRunAt  time.Time `json:"next_run_at"`
+	LastRunAt  time.Time `json:"last_run_at,omitempty"`
+	// LastStatus is "success" or "failed", set after the first run.
+	LastStatus string `json:"last_status,omitempty"`
+	LastError  string `json:"last_error,omitempty"`
+}
+
+// nextRunAt parses expression and returns the next time it fires
+// strictly after from.
+func nextRunAt(expression string, from time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(expression)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression %q: %w", expression, err)
+	}
+	return schedule.Next(from), nil
+}
+
+// Scheduler ticks alongside Worker.Start, running any ScheduledJob whose
+// NextRunAt has passed and persisting its result and next run time back
+// onto the schedule entry.
+type Scheduler struct {
+	queue    Queue
+	service  *converter.Service
+	log      *logger.Logger
+	leaseFor time.Duration
+	popLimit int
+	http     *http.Client
+}
+
+// NewScheduler builds a Scheduler that runs due jobs against service and
+// stores them back in queue.
+func NewScheduler(queue Queue, service *converter.Service, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		queue:    queue,
+		service:  service,
+		log:      log,
+		leaseFor: 30 * time.Second,
+		popLimit: 50,
+		http:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Start polls queue once per second until ctx is canceled, running any
+// due schedules it claims.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.log.Info("Starting scheduler")
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Scheduler shutting down")
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	due, err := s.queue.PopDueSchedules(ctx, time.Now(), s.leaseFor, s.popLimit)
+	if err != nil {
+		s.log.WithError(err).Warn("Failed to poll scheduled jobs")
+		return
+	}
+
+	for _, sched := range due {
+		s.runOne(ctx, sched)
+	}
+}
+
+func (s *Scheduler) runOne(ctx context.Context, sched *ScheduledJob) {
+	resp, err := s.service.Convert(ctx, &sched.Request)
+
+	sched.LastRunAt = time.Now()
+	if err != nil {
+		sched.LastStatus = "failed"
+		sched.LastError = err.Error()
+		s.log.WithError(err).WithField("schedule_id", sched.ID).Error("Scheduled conversion failed")
+	} else {
+		sched.LastStatus = "success"
+		sched.LastError = ""
+	}
+
+	if sched.WebhookURL != "" {
+		s.notifyWebhook(ctx, sched, resp, err)
+	}
+
+	next, parseErr := nextRunAt(sched.Expression, time.Now())
+	if parseErr != nil {
+		s.log.WithError(parseErr).WithField("schedule_id", sched.ID).Error("Invalid cron expression, removing schedule")
+		if unschedErr := s.queue.Unschedule(ctx, sched.ID); unschedErr != nil {
+			s.log.WithError(unschedErr).WithField("schedule_id", sched.ID).Warn("Failed to remove invalid schedule")
+		}
+		return
+	}
+	sched.NextRunAt = next
+
+	if err := s.queue.Schedule(ctx, sched); err != nil {
+		s.log.WithError(err).WithField("schedule_id", sched.ID).Error("Failed to persist schedule after run")
+	}
+}
+
+func (s *Scheduler) notifyWebhook(ctx context.Context, sched *ScheduledJob, resp *converter.ConvertResponse, runErr error) {
+	payload := map[string]interface{}{
+		"schedule_id": sched.ID,
+		"status":      sched.LastStatus,
+	}
+	if runErr != nil {
+		payload["error"] = runErr.Error()
+	} else {
+		payload["result"] = resp
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		s.log.WithError(err).WithField("schedule_id", sched.ID).Warn("Failed to marshal webhook payload")
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sched.WebhookURL, bytes.NewReader(data))
+	if err != nil {
+		s.log.WithError(err).WithField("schedule_id", sched.ID).Warn("Failed to build webhook request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp2, err := s.http.Do(req)
+	if err != nil {
+		s.log.WithError(err).WithField("schedule_id", sched.ID).Warn("Webhook delivery failed")
+		return
+	}
+	resp2.Body.Close()
+}
+
+// memorySchedules holds MemoryQueue's recurring-job bookkeeping, kept
+// separate from the one-off job map since it's indexed and iterated
+// differently.
+type memorySchedules struct {
+	mu        sync.Mutex
+	schedules map[string]*ScheduledJob
+}
+
+func newMemorySchedules() *memorySchedules {
+	return &memorySchedules{schedules: make(map[string]*ScheduledJob)}
+}
+
+func (q *MemoryQueue) Schedule(ctx context.Context, job *ScheduledJob) error {
+	if job.ID == "" {
+		job.ID = generateJobID()
+	}
+
+	q.schedules.mu.Lock()
+	defer q.schedules.mu.Unlock()
+	q.schedules.schedules[job.ID] = job
+	return nil
+}
+
+func (q *MemoryQueue) Unschedule(ctx context.Context, id string) error {
+	q.schedules.mu.Lock()
+	defer q.schedules.mu.Unlock()
+	delete(q.schedules.schedules, id)
+	return nil
+}
+
+func (q *MemoryQueue) ListSchedules(ctx context.Context) ([]*ScheduledJob, error) {
+	q.schedules.mu.Lock()
+	defer q.schedules.mu.Unlock()
+
+	out := make([]*ScheduledJob, 0, len(q.schedules.schedules))
+	for _, sched := range q.schedules.schedules {
+		out = append(out, sched)
+	}
+	return out, nil
+}
+
+// PopDueSchedules claims due schedules in place. MemoryQueue only ever
+// runs in a single process, so there's no lease contention to guard
+// against; leaseFor is accepted purely for interface parity with
+// RedisQueue.
+func (q *MemoryQueue) PopDueSchedules(ctx context.Context, now time.Time, leaseFor time.Duration, limit int) ([]*ScheduledJob, error) {
+	q.schedules.mu.Lock()
+	defer q.schedules.mu.Unlock()
+
+	var due []*ScheduledJob
+	for _, sched := range q.schedules.schedules {
+		if len(due) >= limit {
+			break
+		}
+		if !sched.NextRunAt.After(now) {
+			due = append(due, sched)
+		}
+	}
+	return due, nil
+}
+
+const (
+	redisSchedulesZSet = "schedules:zset"
+	redisSchedulesData = "schedules:data"
+)
+
+func (q *RedisQueue) Schedule(ctx context.Context, job *ScheduledJob) error {
+	if job.ID == "" {
+		job.ID = generateJobID()
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.client.Pipeline()
+	pipe.HSet(ctx, q.prefix+redisSchedulesData, job.ID, data)
+	pipe.ZAdd(ctx, q.prefix+redisSchedulesZSet, redis.Z{Score: float64(job.NextRunAt.Unix()), Member: job.ID})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (q *RedisQueue) Unschedule(ctx context.Context, id string) error {
+	pipe := q.client.Pipeline()
+	pipe.HDel(ctx, q.prefix+redisSchedulesData, id)
+	pipe.ZRem(ctx, q.prefix+redisSchedulesZSet, id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (q *RedisQueue) ListSchedules(ctx context.Context) ([]*ScheduledJob, error) {
+	ids, err := q.client.ZRange(ctx, q.prefix+redisSchedulesZSet, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return q.loadSchedules(ctx, ids)
+}
+
+// popDueSchedulesScript atomically claims due schedules by bumping each
+// one's sorted-set score to a short-lived lease deadline, so multiple
+// RedisQueue-backed Scheduler replicas sharing the same broker never both
+// pop the same due job.
+var popDueSchedulesScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local lease = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local ids = redis.call('ZRANGEBYSCORE', key, '-inf', now, 'LIMIT', 0, limit)
+for i, id in ipairs(ids) do
+	redis.call('ZADD', key, now + lease, id)
+end
+return ids
+`)
+
+func (q *RedisQueue) PopDueSchedules(ctx context.Context, now time.Time, leaseFor time.Duration, limit int) ([]*ScheduledJob, error) {
+	result, err := popDueSchedulesScript.Run(ctx, q.client, []string{q.prefix + redisSchedulesZSet}, now.Unix(), int64(leaseFor.Seconds()), limit).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	raw, ok := result.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if id, ok := v.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return q.loadSchedules(ctx, ids)
+}
+
+func (q *RedisQueue) loadSchedules(ctx context.Context, ids []string) ([]*ScheduledJob, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	raws, err := q.client.HMGet(ctx, q.prefix+redisSchedulesData, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*ScheduledJob, 0, len(ids))
+	for _, raw := range raws {
+		str, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		var sched ScheduledJob
+		if err := json.Unmarshal([]byte(str), &sched); err != nil {
+			continue
+		}
+		out = append(out, &sched)
+	}
+	return out, nil
+}
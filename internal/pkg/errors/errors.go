@@ -1,21 +1,133 @@
+// Package errors provides the structured *Error every HTTP handler in
+// this codebase returns: a stable Code and HTTP Status for the JSON
+// body, an optional cause chain via Unwrap so errors.Is/errors.As see
+// through it, and a Localize method backed by a registry any subsystem
+// can add its own codes and translations to.
 package errors
 
 import (
+	stderrors "errors"
+	"fmt"
 	"net/http"
-
+	"sync"
 )
 
+// Error is a structured application error.
 type Error struct {
 	Code    string                 `json:"code"`
 	Message string                 `json:"message"`
 	Details map[string]interface{} `json:"details,omitempty"`
 	Status  int                    `json:"status"`
+	wrapped error
 }
 
 func (e *Error) Error() string {
+	if e.wrapped != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.wrapped)
+	}
 	return e.Message
 }
 
+// Unwrap exposes the cause WithCause attached, so errors.Is/errors.As
+// can see through an *Error to whatever triggered it.
+func (e *Error) Unwrap() error {
+	return e.wrapped
+}
+
+// Is reports whether target is an *Error with the same Code. Errors
+// built from a shared prototype (ErrParseFailed.WithCause(...), say)
+// are no longer the same pointer as that prototype, so comparing Code
+// rather than identity is what lets errors.Is(err, errors.ErrParseFailed)
+// keep working after WithCause/WithDetails clone it.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// WithCause returns a copy of e with cause attached as its Unwrap
+// target, leaving e itself (typically a package-level prototype like
+// ErrParseFailed) unmodified.
+func (e *Error) WithCause(cause error) *Error {
+	clone := e.clone()
+	clone.wrapped = cause
+	return clone
+}
+
+// WithDetails returns a copy of e with key/value merged into Details.
+func (e *Error) WithDetails(key string, value interface{}) *Error {
+	clone := e.clone()
+	if clone.Details == nil {
+		clone.Details = make(map[string]interface{}, 1)
+	}
+	clone.Details[key] = value
+	return clone
+}
+
+func (e *Error) clone() *Error {
+	clone := *e
+	if e.Details != nil {
+		clone.Details = make(map[string]interface{}, len(e.Details))
+		for k, v := range e.Details {
+			clone.Details[k] = v
+		}
+	}
+	return &clone
+}
+
+// Localize returns e's message translated into lang, falling back to
+// the registered default message, and finally to e.Message itself if
+// e's code was never registered with Register.
+func (e *Error) Localize(lang string) string {
+	desc, ok := Descriptor(e.Code)
+	if !ok {
+		return e.Message
+	}
+	if msg, ok := desc.Locales[lang]; ok {
+		return msg
+	}
+	return desc.Message
+}
+
+// ErrorDescriptor is a registered error code: its default message, HTTP
+// status, and a catalog of per-locale message overrides keyed by
+// language tag (e.g. "zh-CN").
+type ErrorDescriptor struct {
+	Code    string
+	Message string
+	Status  int
+	Locales map[string]string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ErrorDescriptor)
+)
+
+// Register adds code to the shared registry and returns the *Error
+// prototype for it, so a subsystem (parser, subscription, cache, ...)
+// can define its own error codes and translations without editing this
+// package. Registering an already-registered code overwrites its
+// descriptor.
+func Register(code, message string, status int, locales map[string]string) *Error {
+	registryMu.Lock()
+	registry[code] = ErrorDescriptor{Code: code, Message: message, Status: status, Locales: locales}
+	registryMu.Unlock()
+
+	return &Error{Code: code, Message: message, Status: status}
+}
+
+// Descriptor returns the registered descriptor for code, if Register
+// has been called for it.
+func Descriptor(code string) (ErrorDescriptor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	d, ok := registry[code]
+	return d, ok
+}
+
 func New(code string, message string) *Error {
 	return &Error{
 		Code:    code,
@@ -32,14 +144,25 @@ func NewWithStatus(code string, message string, status int) *Error {
 	}
 }
 
+// Wrap builds an *Error describing message whose cause is err. The
+// resulting Code/Status are copied from err when err is (or, via
+// Unwrap, wraps) an *Error already - a parser's
+// ErrParseFailed.WithCause(...) surfacing through a few layers of
+// fmt.Errorf("%w: ...") should still reach the HTTP layer as a 400, not
+// the 500 INTERNAL_ERROR every foreign error defaults to.
 func Wrap(err error, message string) *Error {
+	code, status := "INTERNAL_ERROR", http.StatusInternalServerError
+
+	var appErr *Error
+	if stderrors.As(err, &appErr) {
+		code, status = appErr.Code, appErr.Status
+	}
+
 	return &Error{
-		Code:    "INTERNAL_ERROR",
+		Code:    code,
 		Message: message,
-		Status:  http.StatusInternalServerError,
-		Details: map[string]interface{}{
-			"original_error": err.Error(),
-		},
+		Status:  status,
+		wrapped: err,
 	}
 }
 
@@ -74,15 +197,55 @@ func ValidationError(field string, message string) *Error {
 	}
 }
 
-// Common errors
+// ValidationFieldError is one failure found while validating a strict-mode
+// request: Field is the offending JSON key, Path locates it within the
+// request body (e.g. "options.proxy_groups[0].url"), and Reason explains
+// what's wrong.
+type ValidationFieldError struct {
+	Field  string `json:"field"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// AggregateValidation builds a single 422 *Error carrying every field
+// failure in Details["errors"], for strict-mode validation where callers
+// want the full list instead of bailing out on the first problem.
+func AggregateValidation(fieldErrors []ValidationFieldError) *Error {
+	return &Error{
+		Code:    "VALIDATION_ERROR",
+		Message: fmt.Sprintf("%d validation error(s)", len(fieldErrors)),
+		Status:  http.StatusUnprocessableEntity,
+		Details: map[string]interface{}{
+			"errors": fieldErrors,
+		},
+	}
+}
+
+// Common errors, registered up front so Localize and Descriptor work
+// for them out of the box; subsystem-specific codes register
+// themselves the same way via Register, without editing this file.
 var (
-	ErrInvalidURL       = BadRequest("INVALID_URL", "invalid subscription URL")
-	ErrInvalidFormat    = BadRequest("INVALID_FORMAT", "invalid subscription format")
-	ErrEmptyResponse    = BadRequest("EMPTY_RESPONSE", "empty subscription content")
-	ErrParseFailed      = BadRequest("PARSE_FAILED", "failed to parse subscription")
-	ErrGenerationFailed = InternalError("GENERATION_FAILED", "failed to generate configuration")
-	ErrCacheError       = InternalError("CACHE_ERROR", "cache operation failed")
-	ErrNotFound         = NotFound("NOT_FOUND", "resource not found")
-	ErrTimeout          = InternalError("TIMEOUT", "operation timeout")
-	ErrRateLimit        = NewWithStatus("RATE_LIMIT", "too many requests", http.StatusTooManyRequests)
-)
\ No newline at end of file
+	ErrInvalidURL = Register("INVALID_URL", "invalid subscription URL", http.StatusBadRequest, map[string]string{
+		"zh-CN": "订阅链接无效",
+	})
+	ErrInvalidFormat = Register("INVALID_FORMAT", "invalid subscription format", http.StatusBadRequest, map[string]string{
+		"zh-CN": "订阅格式无效",
+	})
+	ErrEmptyResponse = Register("EMPTY_RESPONSE", "empty subscription content", http.StatusBadRequest, map[string]string{
+		"zh-CN": "订阅内容为空",
+	})
+	ErrParseFailed = Register("PARSE_FAILED", "failed to parse subscription", http.StatusBadRequest, map[string]string{
+		"zh-CN": "订阅解析失败",
+	})
+	ErrGenerationFailed = Register("GENERATION_FAILED", "failed to generate configuration", http.StatusInternalServerError, map[string]string{
+		"zh-CN": "生成配置失败",
+	})
+	ErrCacheError = Register("CACHE_ERROR", "cache operation failed", http.StatusInternalServerError, nil)
+	ErrNotFound   = Register("NOT_FOUND", "resource not found", http.StatusNotFound, map[string]string{
+		"zh-CN": "资源不存在",
+	})
+	ErrTimeout   = Register("TIMEOUT", "operation timeout", http.StatusInternalServerError, nil)
+	ErrRateLimit = Register("RATE_LIMIT", "too many requests", http.StatusTooManyRequests, map[string]string{
+		"zh-CN": "请求过于频繁",
+	})
+)
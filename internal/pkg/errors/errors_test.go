@@ -0,0 +1,72 @@
+package errors
+
+import (
+	stderrors "errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_WithCause_SetsUnwrapWithoutMutatingPrototype(t *testing.T) {
+	cause := stderrors.New("boom")
+	wrapped := ErrParseFailed.WithCause(cause)
+
+	assert.Equal(t, cause, wrapped.Unwrap())
+	assert.Nil(t, ErrParseFailed.Unwrap())
+	assert.True(t, stderrors.Is(wrapped, ErrParseFailed))
+}
+
+func TestError_WithDetails_ClonesDetailsMap(t *testing.T) {
+	withDetails := ErrParseFailed.WithDetails("stage", "base64")
+
+	assert.Equal(t, "base64", withDetails.Details["stage"])
+	assert.Nil(t, ErrParseFailed.Details)
+}
+
+func TestError_Is_ComparesByCodeNotIdentity(t *testing.T) {
+	a := ErrParseFailed.WithCause(stderrors.New("a"))
+	b := ErrParseFailed.WithDetails("stage", "b")
+
+	assert.True(t, stderrors.Is(a, b))
+	assert.False(t, stderrors.Is(a, ErrNotFound))
+}
+
+func TestWrap_PreservesCodeAndStatusFromWrappedError(t *testing.T) {
+	wrapped := Wrap(ErrParseFailed.WithCause(stderrors.New("bad bytes")), "conversion failed")
+
+	assert.Equal(t, ErrParseFailed.Code, wrapped.Code)
+	assert.Equal(t, ErrParseFailed.Status, wrapped.Status)
+	require.Error(t, wrapped.Unwrap())
+}
+
+func TestWrap_DefaultsToInternalErrorForForeignErrors(t *testing.T) {
+	wrapped := Wrap(stderrors.New("plain error"), "something failed")
+
+	assert.Equal(t, "INTERNAL_ERROR", wrapped.Code)
+	assert.Equal(t, http.StatusInternalServerError, wrapped.Status)
+}
+
+func TestRegister_MakesDescriptorAvailable(t *testing.T) {
+	Register("TEST_CODE", "default message", http.StatusTeapot, map[string]string{"zh-CN": "默认消息"})
+
+	desc, ok := Descriptor("TEST_CODE")
+	require.True(t, ok)
+	assert.Equal(t, http.StatusTeapot, desc.Status)
+	assert.Equal(t, "默认消息", desc.Locales["zh-CN"])
+}
+
+func TestError_Localize(t *testing.T) {
+	err := Register("TEST_LOCALIZE", "default message", http.StatusBadRequest, map[string]string{"zh-CN": "默认消息"})
+
+	assert.Equal(t, "默认消息", err.Localize("zh-CN"))
+	assert.Equal(t, "default message", err.Localize("fr-FR"))
+	assert.Equal(t, "default message", err.Localize(""))
+}
+
+func TestError_Localize_UnregisteredCodeFallsBackToMessage(t *testing.T) {
+	err := New("UNREGISTERED", "unregistered message")
+
+	assert.Equal(t, "unregistered message", err.Localize("zh-CN"))
+}
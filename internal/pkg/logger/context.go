@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ctxKey namespaces values this package stores on a context.Context.
+type ctxKey int
+
+const entryKey ctxKey = iota
+
+// NewContext returns a copy of ctx carrying entry, so downstream code can
+// retrieve a request-scoped logger via FromContext without entry being
+// threaded through every function signature.
+func NewContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, entryKey, entry)
+}
+
+// FromContext returns the *logrus.Entry NewContext attached to ctx, or a
+// bare entry off fallback if ctx carries none.
+func FromContext(ctx context.Context, fallback *Logger) *logrus.Entry {
+	if entry, ok := ctx.Value(entryKey).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(fallback.Logger)
+}
+
+// WithRequestID tags l with requestID and returns both the resulting
+// entry and a context carrying it, for middleware that wants to log the
+// ID and propagate it to downstream code in one step.
+func (l *Logger) WithRequestID(ctx context.Context, requestID string) (context.Context, *logrus.Entry) {
+	entry := l.Logger.WithField("request_id", requestID)
+	return NewContext(ctx, entry), entry
+}
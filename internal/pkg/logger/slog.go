@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Slog returns an slog.Logger backed by l, for dependencies written
+// against the standard library's structured-logging interface rather
+// than logrus directly, so they still honor this service's configured
+// level, format, and output.
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(&slogHandler{logger: l.Logger})
+}
+
+// slogHandler adapts slog.Handler onto an existing *logrus.Logger.
+type slogHandler struct {
+	logger *logrus.Logger
+	attrs  []slog.Attr
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.IsLevelEnabled(slogLevelToLogrus(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := make(logrus.Fields, record.NumAttrs()+len(h.attrs))
+	for _, attr := range h.attrs {
+		fields[attr.Key] = attr.Value.Any()
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		fields[attr.Key] = attr.Value.Any()
+		return true
+	})
+
+	h.logger.WithFields(fields).Log(slogLevelToLogrus(record.Level), record.Message)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	merged := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	merged = append(merged, h.attrs...)
+	merged = append(merged, attrs...)
+	return &slogHandler{logger: h.logger, attrs: merged}
+}
+
+// WithGroup is unimplemented beyond a no-op: logrus.Fields has no
+// concept of nested groups, so grouped attributes are flattened instead.
+func (h *slogHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+func slogLevelToLogrus(level slog.Level) logrus.Level {
+	switch {
+	case level >= slog.LevelError:
+		return logrus.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logrus.WarnLevel
+	case level >= slog.LevelInfo:
+		return logrus.InfoLevel
+	default:
+		return logrus.DebugLevel
+	}
+}
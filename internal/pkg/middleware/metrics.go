@@ -64,8 +64,93 @@ var (
 			Help: "Total number of cache misses",
 		},
 	)
+
+	parserAttemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "subconverter_parser_attempts_total",
+			Help: "Total number of parser attempts",
+		},
+		[]string{"type", "result"},
+	)
+
+	parserDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "subconverter_parser_duration_seconds",
+			Help:    "Parser execution duration in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"type"},
+	)
+
+	jobsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "subconverter_worker_jobs_total",
+			Help: "Total number of jobs processed by the worker pool, by terminal status (completed, retried, dead)",
+		},
+		[]string{"type", "status"},
+	)
+
+	jobDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "subconverter_worker_job_duration_seconds",
+			Help:    "Job processing duration in seconds, from Pop to its terminal Complete/Nack",
+			Buckets: subSecondBuckets,
+		},
+		[]string{"type"},
+	)
+
+	generatorAttemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "subconverter_generator_attempts_total",
+			Help: "Total number of generator.Manager.Generate calls, by target format and result",
+		},
+		[]string{"target", "result"},
+	)
+
+	generatorDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "subconverter_generator_duration_seconds",
+			Help:    "Generator execution duration in seconds, by target format",
+			Buckets: subSecondBuckets,
+		},
+		[]string{"target"},
+	)
+
+	httpFetchTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "subconverter_http_fetch_total",
+			Help: "Total number of outbound subscription fetches performed by infra/http.Client",
+		},
+		[]string{"result"},
+	)
+
+	httpFetchDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "subconverter_http_fetch_duration_seconds",
+			Help:    "Outbound subscription fetch duration in seconds",
+			Buckets: subSecondBuckets,
+		},
+		[]string{"result"},
+	)
+
+	queueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "subconverter_queue_depth",
+			Help: "Number of jobs currently waiting to be popped, by queue backend",
+		},
+		[]string{"backend"},
+	)
 )
 
+// subSecondBuckets starts an order of magnitude below prometheus.DefBuckets'
+// 5ms floor, so sub-millisecond work (cache hits, tiny subscriptions)
+// lands in a real bucket instead of every sample piling into the same
+// smallest one.
+var subSecondBuckets = []float64{
+	0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005,
+	0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
 // MetricsMiddleware adds Prometheus metrics to HTTP requests
 func MetricsMiddleware() fiber.Handler {
 	return func(c fiber.Ctx) error {
@@ -116,4 +201,50 @@ func CacheMetrics(hit bool) {
 	} else {
 		cacheMisses.Inc()
 	}
+}
+
+// ParserMetrics records per-protocol parser attempt metrics
+func ParserMetrics(parserType string, duration time.Duration, success bool) {
+	result := "success"
+	if !success {
+		result = "error"
+	}
+
+	parserAttemptsTotal.WithLabelValues(parserType, result).Inc()
+	parserDuration.WithLabelValues(parserType).Observe(duration.Seconds())
+}
+
+// JobMetrics records the terminal outcome of one Worker.processJob call:
+// status is "completed", "retried", or "dead".
+func JobMetrics(jobType, status string, duration time.Duration) {
+	jobsTotal.WithLabelValues(jobType, status).Inc()
+	jobDuration.WithLabelValues(jobType).Observe(duration.Seconds())
+}
+
+// GeneratorMetrics records one generator.Manager.Generate call.
+func GeneratorMetrics(target string, duration time.Duration, success bool) {
+	result := "success"
+	if !success {
+		result = "error"
+	}
+
+	generatorAttemptsTotal.WithLabelValues(target, result).Inc()
+	generatorDuration.WithLabelValues(target).Observe(duration.Seconds())
+}
+
+// HTTPFetchMetrics records one infra/http.Client fetch.
+func HTTPFetchMetrics(duration time.Duration, success bool) {
+	result := "success"
+	if !success {
+		result = "error"
+	}
+
+	httpFetchTotal.WithLabelValues(result).Inc()
+	httpFetchDuration.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// SetQueueDepth publishes backend's current queue depth, polled by
+// queue.StartDepthMetrics.
+func SetQueueDepth(backend string, depth float64) {
+	queueDepth.WithLabelValues(backend).Set(depth)
 }
\ No newline at end of file
@@ -30,6 +30,19 @@ func init() {
 		}
 		return validTypes[value]
 	})
+
+	validate.RegisterValidation("fingerprint", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		if value == "" {
+			return true
+		}
+		validFingerprints := map[string]bool{
+			"chrome": true, "firefox": true, "safari": true,
+			"ios": true, "android": true, "edge": true,
+			"360": true, "qq": true, "random": true,
+		}
+		return validFingerprints[value]
+	})
 }
 
 func Validate(i interface{}) error {
@@ -56,6 +69,8 @@ func getErrorMessage(fe validator.FieldError) string {
 		return fe.Field() + " must be a valid URL"
 	case "proxy_type":
 		return fe.Field() + " must be a valid proxy type"
+	case "fingerprint":
+		return fe.Field() + " must be a valid utls fingerprint"
 	case "min":
 		return fe.Field() + " must be greater than " + fe.Param()
 	case "max":
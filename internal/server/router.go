@@ -1,23 +1,38 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/gofiber/adaptor/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/subconverter/subconverter-go/internal/app/converter"
 	"github.com/subconverter/subconverter-go/internal/infra/config"
 	"github.com/subconverter/subconverter-go/internal/infra/http"
 	"github.com/subconverter/subconverter-go/internal/pkg/errors"
 	"github.com/subconverter/subconverter-go/internal/pkg/validator"
+	"go.uber.org/multierr"
+	"golang.org/x/sync/errgroup"
 )
 
+// requestIDLocalsKey is the fiber Locals key requestID stashes the
+// per-request correlation ID under, for requestContext to read back.
+type requestIDLocalsKey struct{}
+
 // Router manages HTTP routes
 type Router struct {
 	app     *fiber.App
@@ -48,6 +63,7 @@ func NewRouter(service *converter.Service, cfg *config.Config) *Router {
 func (r *Router) SetupRoutes() {
 	// Middleware
 	r.app.Use(recover.New())
+	r.app.Use(r.requestID)
 	r.app.Use(logger.New(logger.Config{
 		Format: "${time} ${method} ${path} - ${status} ${latency}\n",
 	}))
@@ -77,17 +93,74 @@ func (r *Router) SetupRoutes() {
 	api.Post("/convert", r.handleConvert)
 	api.Post("/convert/batch", r.handleBatchConvert)
 	api.Post("/validate", r.handleValidate)
+	api.Post("/shorten", r.handleShorten)
 
 	// Info routes
 	api.Get("/info", r.handleInfo)
 	api.Get("/health", r.handleHealth)
 	api.Get("/formats", r.handleFormats)
 
+	// Rule provider routes
+	api.Get("/providers/:name", r.handleProvider)
+
+	// Short-link routes
+	r.app.Get("/s/:token", r.handleShortLink)
+
+	// Metrics route, so whichever process runs this Router (today just
+	// cmd/subconverter) serves Prometheus scrapes alongside the API
+	// instead of needing a second listener.
+	r.RegisterMetrics()
+
+	// Clash-compatible external controller, mounted at the bare paths
+	// ("/proxies", not "/api/v1/proxies") real Clash clients and
+	// dashboards expect.
+	if r.config.Clash.Enabled {
+		if ctrl := r.service.ClashController(); ctrl != nil {
+			ctrl.Register(r.app)
+		}
+	}
+
 	// Static routes
 	r.app.Get("/", r.handleRoot)
 	r.app.Get("/docs", r.handleDocs)
 }
 
+// RegisterMetrics mounts the Prometheus handler at /metrics. Split out
+// from SetupRoutes so a caller that wants metrics without the rest of the
+// API surface (or on a separate listener) can call it on its own.
+func (r *Router) RegisterMetrics() {
+	r.app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+}
+
+// requestID assigns each request a stable correlation ID (honoring an
+// inbound X-Request-ID so an upstream proxy's ID survives), echoes it
+// back on the response, and stashes it in Locals for requestContext to
+// attach to the handler's context.Context.
+func (r *Router) requestID(c *fiber.Ctx) error {
+	id := c.Get("X-Request-ID")
+	if id == "" {
+		id = uuid.New().String()
+	}
+	c.Locals(requestIDLocalsKey{}, id)
+	c.Set("X-Request-ID", id)
+	return c.Next()
+}
+
+// requestContext builds the context.Context passed to Service.Convert:
+// the incoming User-Agent override plus a request-scoped logger entry
+// tagged with this request's correlation ID, so every log line written
+// while serving it - including from fetchSubscriptions and Generate deep
+// inside the service - can be traced back to it.
+func (r *Router) requestContext(c *fiber.Ctx) context.Context {
+	ctx := context.WithValue(c.Context(), http.UserAgentKey, c.Get("User-Agent"))
+
+	if id, ok := c.Locals(requestIDLocalsKey{}).(string); ok && id != "" {
+		ctx, _ = r.service.Logger().WithRequestID(ctx, id)
+	}
+
+	return ctx
+}
+
 // handleConvert handles single conversion requests
 func (r *Router) handleConvert(c *fiber.Ctx) error {
 	var req converter.ConvertRequest
@@ -99,26 +172,108 @@ func (r *Router) handleConvert(c *fiber.Ctx) error {
 		return r.errorResponse(c, err)
 	}
 
-	// Add user-agent to context
-	userAgent := c.Get("User-Agent")
-	ctx := context.WithValue(c.Context(), http.UserAgentKey, userAgent)
+	strict := req.StrictConfig || r.config.Server.StrictConfig
+	if strict {
+		if err := rejectUnknownFields(c.Body()); err != nil {
+			return r.errorResponse(c, errors.AggregateValidation([]errors.ValidationFieldError{{
+				Field:  "options",
+				Path:   "options",
+				Reason: err.Error(),
+			}}))
+		}
+		if fieldErrors := r.service.ValidateStrict(&req); len(fieldErrors) > 0 {
+			return r.errorResponse(c, errors.AggregateValidation(fieldErrors))
+		}
+	}
 
-	resp, err := r.service.Convert(ctx, &req)
+	resp, err := r.service.Convert(r.requestContext(c), &req)
 	if err != nil {
 		return r.errorResponse(c, err)
 	}
 
-	// Set appropriate content type
-	generator, exists := r.service.GeneratorManager().Get(req.Target)
+	return r.writeConvertResponse(c, req.Target, resp)
+}
+
+// writeConvertResponse sets the headers a generated config is served
+// with and writes it, honoring If-None-Match against its ETag. Shared by
+// handleConvert and handleShortLink so both paths behave identically.
+func (r *Router) writeConvertResponse(c *fiber.Ctx, target string, resp *converter.ConvertResponse) error {
+	generator, exists := r.service.GeneratorManager().Get(target)
 	if !exists {
-		return r.errorResponse(c, fmt.Errorf("unsupported format: %s", req.Target))
+		return r.errorResponse(c, fmt.Errorf("unsupported format: %s", target))
 	}
 	c.Set("Content-Type", generator.ContentType())
-	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=config.%s", req.Target))
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=config.%s", target))
+	if len(resp.ParseWarnings) > 0 {
+		c.Set("X-Subconverter-Parse-Warnings", strings.Join(resp.ParseWarnings, "; "))
+	}
+
+	etag := etagFor(resp.Config)
+	c.Set("ETag", etag)
+	if c.Get("If-None-Match") == etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 
 	return c.SendString(resp.Config)
 }
 
+// etagFor builds a strong ETag from config's content so clients that
+// already hold the last generated output can skip re-downloading it.
+func etagFor(config string) string {
+	sum := sha256.Sum256([]byte(config))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// handleShorten persists a ConvertRequest under a new short-link token,
+// so callers can share GET /s/{token} instead of a full query string.
+func (r *Router) handleShorten(c *fiber.Ctx) error {
+	var req converter.ShortenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return r.errorResponse(c, errors.BadRequest("INVALID_REQUEST", err.Error()))
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		return r.errorResponse(c, err)
+	}
+
+	token, err := r.service.CreateShortLink(c.Context(), &req.Request, req.Passphrase)
+	if err != nil {
+		return r.errorResponse(c, err)
+	}
+
+	return c.JSON(converter.ShortenResponse{Token: token})
+}
+
+// handleShortLink resolves a short-link token back into its stored
+// ConvertRequest and runs it through the same conversion pipeline
+// handleConvert uses.
+func (r *Router) handleShortLink(c *fiber.Ctx) error {
+	token := c.Params("token")
+	passphrase := c.Query("passphrase")
+
+	req, err := r.service.ResolveShortLink(c.Context(), token, passphrase)
+	if err != nil {
+		return r.errorResponse(c, err)
+	}
+
+	resp, err := r.service.Convert(r.requestContext(c), req)
+	if err != nil {
+		return r.errorResponse(c, err)
+	}
+
+	return r.writeConvertResponse(c, req.Target, resp)
+}
+
+// validationErrorCodes are the codes Service.Convert returns for malformed
+// requests rather than genuine conversion failures; FailFast ignores them
+// since retrying or cancelling the batch over them wouldn't help.
+var validationErrorCodes = map[string]bool{
+	"INVALID_REQUEST":    true,
+	"INVALID_TARGET":     true,
+	"INVALID_URLS":       true,
+	"UNSUPPORTED_TARGET": true,
+}
+
 // handleBatchConvert handles batch conversion requests
 func (r *Router) handleBatchConvert(c *fiber.Ctx) error {
 	var req converter.BatchConvertRequest
@@ -130,28 +285,119 @@ func (r *Router) handleBatchConvert(c *fiber.Ctx) error {
 		return r.errorResponse(c, err)
 	}
 
-	results := make([]converter.ConvertResponse, 0, len(req.Requests))
-	errorsList := make([]string, 0)
+	baseCtx := r.requestContext(c)
 
-	// Add user-agent to context
-	userAgent := c.Get("User-Agent")
-	ctx := context.WithValue(c.Context(), http.UserAgentKey, userAgent)
+	ctx, cancel := context.WithCancel(baseCtx)
+	defer cancel()
 
-	for _, convReq := range req.Requests {
-		resp, err := r.service.Convert(ctx, &convReq)
-		if err != nil {
-			errorsList = append(errorsList, err.Error())
+	concurrency := r.config.Parser.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = len(req.Requests)
+	}
+
+	results := make([]converter.ConvertResponse, len(req.Requests))
+	batchErrors := make([]*converter.BatchError, len(req.Requests))
+
+	var (
+		mu     sync.Mutex
+		aggErr error
+		group  errgroup.Group
+	)
+	group.SetLimit(concurrency)
+
+	for i, convReq := range req.Requests {
+		i, convReq := i, convReq
+		group.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			resp, err := r.service.Convert(ctx, &convReq)
+			if err != nil {
+				batchErr := toBatchError(i, err)
+
+				mu.Lock()
+				batchErrors[i] = batchErr
+				aggErr = multierr.Append(aggErr, err)
+				mu.Unlock()
+
+				if req.FailFast && !validationErrorCodes[batchErr.Code] {
+					cancel()
+				}
+				return nil
+			}
+
+			results[i] = *resp
+			return nil
+		})
+	}
+
+	// The goroutines never return an error themselves (failures are recorded
+	// in batchErrors), so Wait only ever reports a panic propagated by errgroup.
+	if err := group.Wait(); err != nil {
+		return r.errorResponse(c, errors.Wrap(err, "batch conversion panicked"))
+	}
+
+	successes := make([]converter.ConvertResponse, 0, len(req.Requests))
+	batchErrList := make([]converter.BatchError, 0)
+	for i := range req.Requests {
+		if batchErrors[i] != nil {
+			batchErrList = append(batchErrList, *batchErrors[i])
 			continue
 		}
-		results = append(results, *resp)
+		successes = append(successes, results[i])
 	}
 
-	return c.JSON(converter.BatchConvertResponse{
-		Results: results,
-		Errors:  errorsList,
+	status := fiber.StatusOK
+	switch {
+	case len(batchErrList) == 0:
+		status = fiber.StatusOK
+	case len(successes) == 0:
+		status = batchErrList[0].Status
+	default:
+		status = fiber.StatusMultiStatus
+	}
+
+	if aggErr != nil {
+		r.service.Logger().WithError(aggErr).Warn("batch conversion had failures")
+	}
+
+	return c.Status(status).JSON(converter.BatchConvertResponse{
+		Results: successes,
+		Errors:  batchErrList,
 	})
 }
 
+// toBatchError converts a service error into the structured BatchError
+// reported to callers, preserving its code/status and, when available
+// (errors.Wrap sets it), the underlying cause that triggered it. errors.As
+// looks through any fmt.Errorf("%w: ...", ...) chain a parser or fetcher
+// built, not just an *errors.Error at the top level.
+func toBatchError(index int, err error) *converter.BatchError {
+	var appErr *errors.Error
+	if !stderrors.As(err, &appErr) {
+		return &converter.BatchError{
+			Index:   index,
+			Code:    "INTERNAL_ERROR",
+			Status:  fiber.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+
+	cause := ""
+	if unwrapped := appErr.Unwrap(); unwrapped != nil {
+		cause = unwrapped.Error()
+	}
+
+	return &converter.BatchError{
+		Index:   index,
+		Code:    appErr.Code,
+		Status:  appErr.Status,
+		Message: appErr.Message,
+		Cause:   cause,
+	}
+}
+
 // handleValidate handles URL validation requests
 func (r *Router) handleValidate(c *fiber.Ctx) error {
 	var req converter.ValidateRequest
@@ -214,6 +460,21 @@ func (r *Router) handleHealth(c *fiber.Ctx) error {
 	})
 }
 
+// handleProvider serves a registered rule provider's cached body, so
+// clients that can't fetch external rule lists themselves can pull them
+// from us instead.
+func (r *Router) handleProvider(c *fiber.Ctx) error {
+	name := c.Params("name")
+
+	body, err := r.service.RuleProviders().Get(c.Context(), name)
+	if err != nil {
+		return r.errorResponse(c, err)
+	}
+
+	c.Set("Content-Type", "text/plain; charset=utf-8")
+	return c.Send(body)
+}
+
 // handleFormats returns supported formats
 func (r *Router) handleFormats(c *fiber.Ctx) error {
 	formats := r.service.SupportedFormats()
@@ -295,11 +556,24 @@ curl http://localhost:8080/api/v1/health
 	return c.SendString(docs)
 }
 
-// errorResponse returns a standardized error response
+// rejectUnknownFields re-decodes body with DisallowUnknownFields so
+// StrictConfig requests fail on JSON keys ConvertRequest/Options/ProxyGroup
+// don't recognize, instead of BodyParser silently dropping them.
+func rejectUnknownFields(body []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	var req converter.ConvertRequest
+	return dec.Decode(&req)
+}
+
+// errorResponse returns a standardized error response, translating
+// appErr.Message per the request's Accept-Language header when the
+// error's code was registered with one (see errors.Register).
 func (r *Router) errorResponse(c *fiber.Ctx, err error) error {
-	if appErr, ok := err.(*errors.Error); ok {
+	var appErr *errors.Error
+	if stderrors.As(err, &appErr) {
 		return c.Status(appErr.Status).JSON(map[string]interface{}{
-			"error":   appErr.Message,
+			"error":   appErr.Localize(c.Get("Accept-Language")),
 			"code":    appErr.Code,
 			"details": appErr.Details,
 		})